@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) CreateCombo(c *gin.Context) {
+	var combo models.Combo
+	if err := c.ShouldBindJSON(&combo); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.CreateCombo(&combo); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Combo created successfully", "combo": combo})
+}
+
+func (controller *ControllerImplementation) UpdateCombo(c *gin.Context) {
+	var combo models.Combo
+	if err := c.ShouldBindJSON(&combo); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.UpdateCombo(&combo); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Combo updated successfully"})
+}
+
+func (controller *ControllerImplementation) DeleteCombo(c *gin.Context) {
+	if err := controller.Usecases.DeleteCombo(c.Param("id")); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Combo deleted successfully"})
+}
+
+func (controller *ControllerImplementation) GetComboById(c *gin.Context) {
+	combo, err := controller.Usecases.GetComboById(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Combo not found"})
+		return
+	}
+	c.JSON(200, combo)
+}
+
+func (controller *ControllerImplementation) GetAllCombos(c *gin.Context) {
+	combos, err := controller.Usecases.GetAllCombos()
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Combos not found"})
+		return
+	}
+	c.JSON(200, combos)
+}
@@ -27,10 +27,89 @@ type ContollerInterface interface {
 
 	CreateOrder(ctx *gin.Context)
 	UpdateOrder(ctx *gin.Context)
-	DeleteOrder(ctx *gin.Context)
+	TransitionOrder(ctx *gin.Context)
+	VoidOrder(ctx *gin.Context)
+	ApproveVoid(ctx *gin.Context)
+	RejectVoid(ctx *gin.Context)
+	GetPendingVoids(ctx *gin.Context)
 	GetOrderById(ctx *gin.Context)
 	GetAllOrders(ctx *gin.Context)
 	GetAllMyOrders(ctx *gin.Context)
+	GetOrderHistory(ctx *gin.Context)
+	AssignDriver(ctx *gin.Context)
+	FireCourse(ctx *gin.Context)
+	ServeCourse(ctx *gin.Context)
+	GetOrderCourses(ctx *gin.Context)
+	StreamOrders(ctx *gin.Context)
+
+	GetPublicMenu(ctx *gin.Context)
+	GetTableQRCode(ctx *gin.Context)
+
+	IssueGuestToken(ctx *gin.Context)
+	CreateGuestOrder(ctx *gin.Context)
+	GetGuestOrders(ctx *gin.Context)
+
+	CreateTable(ctx *gin.Context)
+	UpdateTable(ctx *gin.Context)
+	DeleteTable(ctx *gin.Context)
+	GetTableById(ctx *gin.Context)
+	GetAllTables(ctx *gin.Context)
+	TransferOrder(ctx *gin.Context)
+	MergeTables(ctx *gin.Context)
+
+	CreateReservation(ctx *gin.Context)
+	UpdateReservation(ctx *gin.Context)
+	GetReservationById(ctx *gin.Context)
+	GetDailyReservations(ctx *gin.Context)
+	ConfirmReservation(ctx *gin.Context)
+	SeatReservation(ctx *gin.Context)
+	NoShowReservation(ctx *gin.Context)
+	CancelReservation(ctx *gin.Context)
+
+	JoinWaitlist(ctx *gin.Context)
+	GetWaitlist(ctx *gin.Context)
+	SeatWaitlistParty(ctx *gin.Context)
+	WalkAwayWaitlistParty(ctx *gin.Context)
+
+	CreateCustomer(ctx *gin.Context)
+	UpdateCustomer(ctx *gin.Context)
+	GetCustomerById(ctx *gin.Context)
+	GetCustomerByPhone(ctx *gin.Context)
+	GetCustomerPoints(ctx *gin.Context)
+	AttachCustomer(ctx *gin.Context)
+
+	IssueVoucher(ctx *gin.Context)
+	GetVoucher(ctx *gin.Context)
+	GetVoucherLedger(ctx *gin.Context)
+	GetVoucherQRCode(ctx *gin.Context)
+
+	SplitOrder(ctx *gin.Context)
+	SettleBillPart(ctx *gin.Context)
+
+	RecordPayment(ctx *gin.Context)
+	GetOrderPayments(ctx *gin.Context)
+	RefundPayment(ctx *gin.Context)
+	ReconcileCash(ctx *gin.Context)
+	GetReconciliations(ctx *gin.Context)
+	RecordTip(ctx *gin.Context)
+	GetOrderTips(ctx *gin.Context)
+	TipPayout(ctx *gin.Context)
+
+	GetReceipt(ctx *gin.Context)
+
+	GetSettings(ctx *gin.Context)
+	UpdateSettings(ctx *gin.Context)
+
+	CreatePricingRule(ctx *gin.Context)
+	UpdatePricingRule(ctx *gin.Context)
+	DeletePricingRule(ctx *gin.Context)
+	GetPricingRuleById(ctx *gin.Context)
+	GetPricingRules(ctx *gin.Context)
+
+	CreateMenuSchedule(ctx *gin.Context)
+	UpdateMenuSchedule(ctx *gin.Context)
+	DeleteMenuSchedule(ctx *gin.Context)
+	GetMenuSchedules(ctx *gin.Context)
 
 	CreateFood(ctx *gin.Context)
 	UpdateFood(ctx *gin.Context)
@@ -43,4 +122,12 @@ type ContollerInterface interface {
 	DeleteDrink(ctx *gin.Context)
 	GetDrinkById(ctx *gin.Context)
 	GetAllDrinks(ctx *gin.Context)
+	SetFoodAvailability(ctx *gin.Context)
+	SetDrinkAvailability(ctx *gin.Context)
+
+	CreateCombo(ctx *gin.Context)
+	UpdateCombo(ctx *gin.Context)
+	DeleteCombo(ctx *gin.Context)
+	GetComboById(ctx *gin.Context)
+	GetAllCombos(ctx *gin.Context)
 }
@@ -1,10 +1,11 @@
 package controllers
 
 import (
-	"sync"
+	"errors"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/yesetoda/kushena/infrastructures/token_services"
 	"github.com/yesetoda/kushena/models"
@@ -26,31 +27,32 @@ func (controller *ControllerImplementation) Help(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Help for the Restaurant API"})
 }
 
-func (controller *ControllerImplementation) FoodTotalPrice(items []models.FoodOrder, price *float64, wg *sync.WaitGroup, mu *sync.Mutex, errChan chan error) {
-	for i := range items {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			items[i].TotalPrice = items[i].Price * items[i].Quantity
-			mu.Lock()
-			*price += items[i].TotalPrice
-			mu.Unlock()
-		}(i)
+// orderError maps usecase errors from the order flow to a response status.
+func (controller *ControllerImplementation) orderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrItemNotFound), errors.Is(err, usecases.ErrOrderNotFound),
+		errors.Is(err, usecases.ErrTableNotFound), errors.Is(err, usecases.ErrPaymentNotFound),
+		errors.Is(err, usecases.ErrReservationNotFound), errors.Is(err, usecases.ErrWaitlistEntryNotFound),
+		errors.Is(err, usecases.ErrCustomerNotFound), errors.Is(err, usecases.ErrVoucherNotFound),
+		errors.Is(err, usecases.ErrPricingRuleNotFound), errors.Is(err, usecases.ErrMenuScheduleNotFound):
+		c.JSON(404, gin.H{"error": err.Error()})
+	case errors.Is(err, usecases.ErrInvalidTransition), errors.Is(err, usecases.ErrOrderClosed),
+		errors.Is(err, usecases.ErrTableInUse), errors.Is(err, usecases.ErrVersionConflict),
+		errors.Is(err, usecases.ErrTableBooked), errors.Is(err, usecases.ErrCustomerExists),
+		errors.Is(err, usecases.ErrItemUnavailable):
+		c.JSON(409, gin.H{"error": err.Error()})
+	default:
+		c.JSON(400, gin.H{"error": err.Error()})
 	}
 }
 
-func (controller *ControllerImplementation) DrinkTotalPrice(items []models.DrinkOrder, price *float64, wg *sync.WaitGroup, mu *sync.Mutex, errChan chan error) {
-	for i := range items {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			items[i].TotalPrice = items[i].Price * items[i].Quantity
-			mu.Lock()
-			*price += items[i].TotalPrice
-			mu.Unlock()
-		}(i)
-	}
+// isManager reports whether an employee is a manager by their stored role,
+// not the one in their token, which outlives a change of role.
+func (controller *ControllerImplementation) isManager(employeeId primitive.ObjectID) bool {
+	employee, err := controller.Usecases.GetEmployeeById(employeeId.Hex())
+	return err == nil && employee != nil && employee.Role == "Manager"
 }
+
 func (controller *ControllerImplementation) CreateOrder(c *gin.Context) {
 	var order models.Order
 	if err := c.ShouldBindJSON(&order); err != nil {
@@ -66,79 +68,70 @@ func (controller *ControllerImplementation) CreateOrder(c *gin.Context) {
 	order.EmployeeId = claim.ID
 	order.CreatedAt = time.Now().UTC()
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var price float64
-	errChan := make(chan error, len(order.Foods)+len(order.Drinks))
-
-	controller.FoodTotalPrice(order.Foods, &price, &wg, &mu, errChan)
-	controller.DrinkTotalPrice(order.Drinks, &price, &wg, &mu, errChan)
-
-	wg.Wait()
-	close(errChan)
-
-	for err := range errChan {
-		if err != nil {
-			c.JSON(404, gin.H{"error": "Item not found"})
-			return
-		}
-	}
-
-	order.TotalPrice = price
-	order.Status = "pending"
-
-	if err := controller.Usecases.CreateOrder(order); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+	created, err := controller.Usecases.CreateOrder(order)
+	if err != nil {
+		controller.orderError(c, err)
 		return
 	}
 
-	c.JSON(200, gin.H{"message": "Order created successfully"})
+	c.JSON(200, gin.H{"message": "Order created successfully", "order": created})
 }
 
 func (controller *ControllerImplementation) UpdateOrder(c *gin.Context) {
-	var order models.Order
-	if err := c.ShouldBindJSON(&order); err != nil {
+	var patch models.OrderPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var price float64
-	errChan := make(chan error, len(order.Foods)+len(order.Drinks))
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
 
-	controller.FoodTotalPrice(order.Foods, &price, &wg, &mu, errChan)
-	controller.DrinkTotalPrice(order.Drinks, &price, &wg, &mu, errChan)
+	order, err := controller.Usecases.UpdateOrder(patch, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
 
-	wg.Wait()
-	close(errChan)
+	c.JSON(200, gin.H{"message": "Order updated successfully", "order": order})
+}
 
-	for err := range errChan {
-		if err != nil {
-			c.JSON(404, gin.H{"error": "Item not found"})
-			return
-		}
+func (controller *ControllerImplementation) GetOrderHistory(c *gin.Context) {
+	history, err := controller.Usecases.GetOrderHistory(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
 	}
+	c.JSON(200, history)
+}
 
-	order.TotalPrice = price
-
-	if err := controller.Usecases.UpdateOrder(&order); err != nil {
+func (controller *ControllerImplementation) TransitionOrder(c *gin.Context) {
+	var body struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(200, gin.H{"message": "Order updated successfully"})
-}
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
 
-func (controller *ControllerImplementation) DeleteOrder(c *gin.Context) {
-	id := c.Param("id")
-	if err := controller.Usecases.DeleteOrder(id); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+	order, err := controller.Usecases.TransitionOrder(c.Param("id"), body.Status, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
 		return
 	}
-	c.JSON(200, gin.H{"message": "Order deleted successfully"})
+	c.JSON(200, order)
 }
 
+
 func (controller *ControllerImplementation) GetOrderById(c *gin.Context) {
 	id := c.Param("id")
 	order, err := controller.Usecases.GetOrderById(id)
@@ -150,12 +143,17 @@ func (controller *ControllerImplementation) GetOrderById(c *gin.Context) {
 }
 
 func (controller *ControllerImplementation) GetAllOrders(c *gin.Context) {
-	orders, err := controller.Usecases.GetAllOrders()
+	query, err := parseOrderQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := controller.Usecases.FindOrders(query)
 	if err != nil {
-		c.JSON(404, gin.H{"error": "Orders not found"})
+		controller.orderError(c, err)
 		return
 	}
-	c.JSON(200, orders)
+	c.JSON(200, page)
 }
 
 func (controller *ControllerImplementation) GetAllMyOrders(c *gin.Context) {
@@ -164,12 +162,18 @@ func (controller *ControllerImplementation) GetAllMyOrders(c *gin.Context) {
 		c.JSON(401, gin.H{"error": "Unauthorized"})
         return
     }
-	orders, err := controller.Usecases.GetAllMyOrders(claim.ID.Hex())
+	query, err := parseOrderQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	query.EmployeeId = claim.ID
+	page, err := controller.Usecases.FindOrders(query)
 	if err != nil {
-		c.JSON(404, gin.H{"error": "Orders not found"})
+		controller.orderError(c, err)
 		return
 	}
-	c.JSON(200, orders)
+	c.JSON(200, page)
 }
 
 func (controller *ControllerImplementation) CreateFood(c *gin.Context) {
@@ -222,7 +226,11 @@ func (controller *ControllerImplementation) GetFoodById(c *gin.Context) {
 }
 
 func (controller *ControllerImplementation) GetAllFoods(c *gin.Context) {
-	foods, err := controller.Usecases.GetAllFoods()
+	all, ok := controller.listAll(c)
+	if !ok {
+		return
+	}
+	foods, err := controller.Usecases.GetAllFoods(all)
 	if err != nil {
 		c.JSON(404, gin.H{"error": "Foods not found"})
 		return
@@ -280,10 +288,61 @@ func (controller *ControllerImplementation) GetDrinkById(c *gin.Context) {
 }
 
 func (controller *ControllerImplementation) GetAllDrinks(c *gin.Context) {
-	drinks, err := controller.Usecases.GetAllDrinks()
+	all, ok := controller.listAll(c)
+	if !ok {
+		return
+	}
+	drinks, err := controller.Usecases.GetAllDrinks(all)
 	if err != nil {
 		c.JSON(404, gin.H{"error": "Drinks not found"})
 		return
 	}
 	c.JSON(200, drinks)
 }
+
+func (controller *ControllerImplementation) GetSettings(c *gin.Context) {
+	settings, err := controller.Usecases.GetSettings()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, settings)
+}
+
+func (controller *ControllerImplementation) UpdateSettings(c *gin.Context) {
+	var settings models.Settings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.UpdateSettings(&settings); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Settings updated successfully", "settings": settings})
+}
+
+func (controller *ControllerImplementation) AssignDriver(c *gin.Context) {
+	var body struct {
+		DriverId primitive.ObjectID `json:"driver_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.AssignDriver(c.Param("id"), body.DriverId, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+)
+
+func (controller *ControllerImplementation) FireCourse(c *gin.Context) {
+	course, err := strconv.Atoi(c.Param("course"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid course number"})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.FireCourse(c.Param("id"), course, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
+
+func (controller *ControllerImplementation) ServeCourse(c *gin.Context) {
+	course, err := strconv.Atoi(c.Param("course"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid course number"})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.ServeCourse(c.Param("id"), course, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
+
+func (controller *ControllerImplementation) GetOrderCourses(c *gin.Context) {
+	courses, err := controller.Usecases.GetOrderCourses(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, courses)
+}
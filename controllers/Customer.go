@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) CreateCustomer(c *gin.Context) {
+	var customer models.Customer
+	if err := c.ShouldBindJSON(&customer); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.CreateCustomer(&customer); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Customer created successfully", "customer": customer})
+}
+
+func (controller *ControllerImplementation) UpdateCustomer(c *gin.Context) {
+	var customer models.Customer
+	if err := c.ShouldBindJSON(&customer); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := controller.Usecases.UpdateCustomer(&customer)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Customer updated successfully", "customer": updated})
+}
+
+func (controller *ControllerImplementation) GetCustomerById(c *gin.Context) {
+	customer, err := controller.Usecases.GetCustomerById(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, customer)
+}
+
+// GetCustomerByPhone looks a customer up by ?phone=.
+func (controller *ControllerImplementation) GetCustomerByPhone(c *gin.Context) {
+	phone := c.Query("phone")
+	if phone == "" {
+		c.JSON(400, gin.H{"error": "phone is required"})
+		return
+	}
+	customer, err := controller.Usecases.GetCustomerByPhone(phone)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, customer)
+}
+
+func (controller *ControllerImplementation) GetCustomerPoints(c *gin.Context) {
+	entries, err := controller.Usecases.GetCustomerPoints(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, entries)
+}
+
+func (controller *ControllerImplementation) AttachCustomer(c *gin.Context) {
+	var body struct {
+		Phone string `json:"phone" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.AttachCustomer(c.Param("id"), body.Phone, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/models"
+	"github.com/yesetoda/kushena/usecases"
+)
+
+// guestClaims returns the claims GuestMiddleware stored for the request.
+func guestClaims(c *gin.Context) (*models.GuestClaims, bool) {
+	value, ok := c.Get("guest")
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*models.GuestClaims)
+	return claims, ok
+}
+
+func (controller *ControllerImplementation) IssueGuestToken(c *gin.Context) {
+	token, err := controller.Usecases.IssueGuestToken(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, token)
+}
+
+func (controller *ControllerImplementation) CreateGuestOrder(c *gin.Context) {
+	claims, ok := guestClaims(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var order models.Order
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	order.CreatedAt = time.Now().UTC()
+
+	created, err := controller.Usecases.CreateGuestOrder(claims, order)
+	if errors.Is(err, usecases.ErrGuestTokenExpired) {
+		c.JSON(401, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Order sent, a waiter will confirm it shortly", "order": created})
+}
+
+func (controller *ControllerImplementation) GetGuestOrders(c *gin.Context) {
+	claims, ok := guestClaims(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+	orders, err := controller.Usecases.GetGuestOrders(claims)
+	if errors.Is(err, usecases.ErrGuestTokenExpired) {
+		c.JSON(401, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, orders)
+}
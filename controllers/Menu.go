@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagMatches reports whether an If-None-Match header names etag.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPublicMenu serves the menu to guests without a login. The ETag is a hash
+// of the body, so clients revalidate cheaply and get 304 until the menu changes.
+func (controller *ControllerImplementation) GetPublicMenu(c *gin.Context) {
+	menu, err := controller.Usecases.GetPublicMenu()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "could not load the menu"})
+		return
+	}
+	body, err := json.Marshal(menu)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=60, must-revalidate")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(304)
+		return
+	}
+	c.Data(200, "application/json; charset=utf-8", body)
+}
+
+func (controller *ControllerImplementation) GetTableQRCode(c *gin.Context) {
+	code, err := controller.Usecases.TableQRCode(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.Data(200, "image/png", code)
+}
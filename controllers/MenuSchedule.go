@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+// listAll reports whether a menu listing asks for sold out and off-schedule
+// items too with ?all=true. Only managers may; for anyone else it writes the
+// response and reports ok as false.
+func (controller *ControllerImplementation) listAll(c *gin.Context) (all, ok bool) {
+	if c.Query("all") != "true" {
+		return false, true
+	}
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return false, false
+	}
+	if !controller.isManager(claim.ID) {
+		c.JSON(403, gin.H{"error": "Only managers can list unavailable items"})
+		return false, false
+	}
+	return true, true
+}
+
+func (controller *ControllerImplementation) SetFoodAvailability(c *gin.Context) {
+	var body models.Availability
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	food, err := controller.Usecases.SetFoodSoldOut(c.Param("id"), body.SoldOut)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, food)
+}
+
+func (controller *ControllerImplementation) SetDrinkAvailability(c *gin.Context) {
+	var body models.Availability
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	drink, err := controller.Usecases.SetDrinkSoldOut(c.Param("id"), body.SoldOut)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, drink)
+}
+
+func (controller *ControllerImplementation) CreateMenuSchedule(c *gin.Context) {
+	var schedule models.MenuSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.CreateMenuSchedule(&schedule); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Menu schedule created successfully", "menu_schedule": schedule})
+}
+
+func (controller *ControllerImplementation) UpdateMenuSchedule(c *gin.Context) {
+	var schedule models.MenuSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.UpdateMenuSchedule(&schedule); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Menu schedule updated successfully", "menu_schedule": schedule})
+}
+
+func (controller *ControllerImplementation) DeleteMenuSchedule(c *gin.Context) {
+	if err := controller.Usecases.DeleteMenuSchedule(c.Param("id")); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Menu schedule deleted successfully"})
+}
+
+func (controller *ControllerImplementation) GetMenuSchedules(c *gin.Context) {
+	schedules, err := controller.Usecases.GetMenuSchedules()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, schedules)
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// parseQueryTime reads an RFC 3339 time or a local YYYY-MM-DD date. A date
+// given as the end of a range covers that whole day.
+func parseQueryTime(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+// parseOrderQuery reads the order listing filters from the query string.
+func parseOrderQuery(c *gin.Context) (models.OrderQuery, error) {
+	query := models.OrderQuery{
+		Type:   c.Query("type"),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+	var err error
+	if v := c.Query("from"); v != "" {
+		if query.From, err = parseQueryTime(v, false); err != nil {
+			return query, fmt.Errorf("invalid from: %v", err)
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if query.To, err = parseQueryTime(v, true); err != nil {
+			return query, fmt.Errorf("invalid to: %v", err)
+		}
+	}
+	if v := c.Query("status"); v != "" {
+		query.Statuses = strings.Split(v, ",")
+	}
+	if v := c.Query("table"); v != "" {
+		if query.TableNumber, err = strconv.Atoi(v); err != nil {
+			return query, fmt.Errorf("invalid table: %v", err)
+		}
+	}
+	if v := c.Query("employee"); v != "" {
+		if query.EmployeeId, err = primitive.ObjectIDFromHex(v); err != nil {
+			return query, fmt.Errorf("invalid employee: %v", err)
+		}
+	}
+	if v := c.Query("item"); v != "" {
+		if query.ItemId, err = primitive.ObjectIDFromHex(v); err != nil {
+			return query, fmt.Errorf("invalid item: %v", err)
+		}
+	}
+	if v := c.Query("min_total"); v != "" {
+		total, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return query, fmt.Errorf("invalid min_total: %v", err)
+		}
+		query.MinTotal = &total
+	}
+	if v := c.Query("max_total"); v != "" {
+		total, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return query, fmt.Errorf("invalid max_total: %v", err)
+		}
+		query.MaxTotal = &total
+	}
+	if v := c.Query("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil {
+			return query, fmt.Errorf("invalid limit: %v", err)
+		}
+	}
+	return query, nil
+}
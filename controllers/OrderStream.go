@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// forStation trims an order down to the lines a station prepares. The kitchen
+// only sees foods and the bar only sees drinks, and neither sees lines held
+// for a later course or guest orders no waiter has confirmed yet. New orders
+// with nothing for the station are skipped; later events are always sent so a
+// screen can drop or refresh a ticket it already shows.
+func forStation(event models.OrderEvent, station string) (models.OrderEvent, bool) {
+	if station == "" {
+		return event, true
+	}
+	if event.Order.Status == models.OrderNeedsConfirmation {
+		event.Order.Foods, event.Order.Drinks = nil, nil
+		return event, event.Type != models.EventOrderCreated
+	}
+	var foods []models.FoodOrder
+	for _, food := range event.Order.Foods {
+		if !food.Held {
+			foods = append(foods, food)
+		}
+	}
+	var drinks []models.DrinkOrder
+	for _, drink := range event.Order.Drinks {
+		if !drink.Held {
+			drinks = append(drinks, drink)
+		}
+	}
+	event.Order.Foods, event.Order.Drinks = foods, drinks
+
+	switch station {
+	case "kitchen":
+		event.Order.Drinks = nil
+		return event, event.Type != models.EventOrderCreated || len(event.Order.Foods) > 0
+	case "bar":
+		event.Order.Foods = nil
+		return event, event.Type != models.EventOrderCreated || len(event.Order.Drinks) > 0
+	}
+	return event, true
+}
+
+// A stream missing an event waits up to gapRetries times gapWait for it to be
+// stored before skipping it.
+const (
+	gapRetries = 5
+	gapWait    = 100 * time.Millisecond
+)
+
+// StreamOrders pushes order events to kitchen and bar displays as server-sent
+// events. ?station=kitchen or ?station=bar limits the lines sent. A display
+// that reconnects resumes after the Last-Event-ID header or ?after=<seq>;
+// without a cursor only new events are sent.
+func (controller *ControllerImplementation) StreamOrders(c *gin.Context) {
+	station := c.Query("station")
+	if station != "" && station != "kitchen" && station != "bar" {
+		c.JSON(400, gin.H{"error": "station must be kitchen or bar"})
+		return
+	}
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("after")
+	}
+	var last int64
+	resume := cursor != ""
+	if resume {
+		seq, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "invalid resume cursor"})
+			return
+		}
+		last = seq
+	}
+
+	from := last
+	if !resume {
+		from = math.MaxInt64
+	}
+	backlog, ch, err := controller.Usecases.SubscribeOrderEvents(from)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer controller.Usecases.UnsubscribeOrderEvents(ch)
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	send := func(event models.OrderEvent) {
+		if event.Seq <= last {
+			return
+		}
+		last = event.Seq
+		if event, ok := forStation(event, station); ok {
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatInt(event.Seq, 10),
+				Event: event.Type,
+				Data:  event,
+			})
+		}
+	}
+	// fill sends the stored events between last and seq in order. Sequence
+	// numbers are taken before events are stored, so a later event can arrive
+	// first; a missing one is waited for a little, and given up on only if it
+	// never shows, as when storing it failed.
+	fill := func(seq int64) error {
+		for attempt := 0; last+1 < seq; attempt++ {
+			missed, err := controller.Usecases.GetOrderEventsAfter(last)
+			if err != nil {
+				return err
+			}
+			for _, m := range missed {
+				if m.Seq >= seq || (m.Seq != last+1 && attempt < gapRetries) {
+					break
+				}
+				send(m)
+			}
+			if attempt == gapRetries {
+				break
+			}
+			if last+1 < seq {
+				time.Sleep(gapWait)
+			}
+		}
+		return nil
+	}
+	deliver := func(event models.OrderEvent) error {
+		if event.Seq > last+1 {
+			if err := fill(event.Seq); err != nil {
+				return err
+			}
+		}
+		send(event)
+		return nil
+	}
+	for _, event := range backlog {
+		if err := deliver(event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-ch:
+			if !ok {
+				// dropped for falling behind, the client reconnects with its cursor
+				return false
+			}
+			if !resume {
+				resume = true
+				last = event.Seq - 1
+			}
+			return deliver(event) == nil
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			return true
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) RecordPayment(c *gin.Context) {
+	var request models.PaymentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	payment, err := controller.Usecases.RecordPayment(c.Param("id"), request, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, payment)
+}
+
+func (controller *ControllerImplementation) GetOrderPayments(c *gin.Context) {
+	payments, err := controller.Usecases.GetOrderPayments(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, payments)
+}
+
+func (controller *ControllerImplementation) RefundPayment(c *gin.Context) {
+	var request models.RefundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	payment, err := controller.Usecases.RefundPayment(c.Param("id"), request, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, payment)
+}
+
+func (controller *ControllerImplementation) ReconcileCash(c *gin.Context) {
+	var request models.ReconciliationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reconciliation, err := controller.Usecases.ReconcileCash(claim.ID, request)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, reconciliation)
+}
+
+// GetReconciliations lists the cash reconciliations of ?date=YYYY-MM-DD, today by default.
+func (controller *ControllerImplementation) GetReconciliations(c *gin.Context) {
+	var day time.Time
+	if date := c.Query("date"); date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "date must be YYYY-MM-DD"})
+			return
+		}
+		day = parsed
+	}
+	reconciliations, err := controller.Usecases.GetReconciliations(day)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, reconciliations)
+}
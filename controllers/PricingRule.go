@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) CreatePricingRule(c *gin.Context) {
+	var rule models.PricingRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := controller.Usecases.CreatePricingRule(&rule, claim.ID); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Pricing rule created successfully", "pricing_rule": rule})
+}
+
+func (controller *ControllerImplementation) UpdatePricingRule(c *gin.Context) {
+	var rule models.PricingRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := controller.Usecases.UpdatePricingRule(&rule)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Pricing rule updated successfully", "pricing_rule": updated})
+}
+
+func (controller *ControllerImplementation) DeletePricingRule(c *gin.Context) {
+	if err := controller.Usecases.DeletePricingRule(c.Param("id")); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Pricing rule deleted successfully"})
+}
+
+func (controller *ControllerImplementation) GetPricingRuleById(c *gin.Context) {
+	rule, err := controller.Usecases.GetPricingRuleById(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, rule)
+}
+
+func (controller *ControllerImplementation) GetPricingRules(c *gin.Context) {
+	rules, err := controller.Usecases.GetPricingRules()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, rules)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/receipt_services"
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+)
+
+// GetReceipt renders an order's receipt. ?format is text (default), escpos or
+// pdf and ?paper is the roll width in millimetres, 58 or 80 (default).
+func (controller *ControllerImplementation) GetReceipt(c *gin.Context) {
+	format := c.DefaultQuery("format", "text")
+	paper := c.DefaultQuery("paper", "80")
+	if paper != "58" && paper != "80" {
+		c.JSON(400, gin.H{"error": "paper must be 58 or 80"})
+		return
+	}
+	paperMM := 80
+	if paper == "58" {
+		paperMM = 58
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	receipt, err := controller.Usecases.GetReceipt(c.Param("id"), claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+
+	width := receipt_services.ColumnsFor(paperMM)
+	name := fmt.Sprintf("receipt-%06d", receipt.Number)
+	switch format {
+	case "text":
+		c.Data(200, "text/plain; charset=utf-8", receipt.Text(width))
+	case "escpos":
+		c.Header("Content-Disposition", "attachment; filename="+name+".bin")
+		c.Data(200, "application/octet-stream", receipt.ESCPOS(width))
+	case "pdf":
+		c.Header("Content-Disposition", "inline; filename="+name+".pdf")
+		c.Data(200, "application/pdf", receipt.PDF(paperMM))
+	default:
+		c.JSON(400, gin.H{"error": "format must be text, escpos or pdf"})
+	}
+}
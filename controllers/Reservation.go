@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) CreateReservation(c *gin.Context) {
+	var reservation models.Reservation
+	if err := c.ShouldBindJSON(&reservation); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := controller.Usecases.CreateReservation(&reservation, claim.ID); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Reservation created successfully", "reservation": reservation})
+}
+
+func (controller *ControllerImplementation) UpdateReservation(c *gin.Context) {
+	var reservation models.Reservation
+	if err := c.ShouldBindJSON(&reservation); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.UpdateReservation(&reservation); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Reservation updated successfully", "reservation": reservation})
+}
+
+func (controller *ControllerImplementation) GetReservationById(c *gin.Context) {
+	reservation, err := controller.Usecases.GetReservationById(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, reservation)
+}
+
+// GetDailyReservations lists the reservations of ?date=YYYY-MM-DD in the
+// restaurant's timezone, today by default.
+func (controller *ControllerImplementation) GetDailyReservations(c *gin.Context) {
+	var day time.Time
+	if date := c.Query("date"); date != "" {
+		settings, err := controller.Usecases.GetSettings()
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", date, settings.Location())
+		if err != nil {
+			c.JSON(400, gin.H{"error": "date must be YYYY-MM-DD"})
+			return
+		}
+		day = parsed
+	}
+	reservations, err := controller.Usecases.GetDailyReservations(day)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, reservations)
+}
+
+func (controller *ControllerImplementation) ConfirmReservation(c *gin.Context) {
+	controller.reservationAction(c, func(id string, table int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+		return controller.Usecases.ConfirmReservation(id, table, employeeId)
+	})
+}
+
+func (controller *ControllerImplementation) SeatReservation(c *gin.Context) {
+	controller.reservationAction(c, func(id string, table int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+		return controller.Usecases.SeatReservation(id, table, employeeId)
+	})
+}
+
+func (controller *ControllerImplementation) NoShowReservation(c *gin.Context) {
+	controller.reservationAction(c, func(id string, _ int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+		return controller.Usecases.NoShowReservation(id, employeeId)
+	})
+}
+
+func (controller *ControllerImplementation) CancelReservation(c *gin.Context) {
+	controller.reservationAction(c, func(id string, _ int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+		return controller.Usecases.CancelReservation(id, employeeId)
+	})
+}
+
+// reservationAction runs a host action on the reservation in the path. The
+// body may carry a {"table_number"} to confirm or seat the party at.
+func (controller *ControllerImplementation) reservationAction(c *gin.Context, action func(id string, table int, employeeId primitive.ObjectID) (*models.Reservation, error)) {
+	var body struct {
+		TableNumber int `json:"table_number"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reservation, err := action(c.Param("id"), body.TableNumber, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, reservation)
+}
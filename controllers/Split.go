@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) SplitOrder(c *gin.Context) {
+	var request models.SplitRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.SplitOrder(c.Param("id"), request, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
+
+func (controller *ControllerImplementation) SettleBillPart(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("part"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid part number"})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.SettleBillPart(c.Param("id"), number, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) CreateTable(c *gin.Context) {
+	var table models.Table
+	if err := c.ShouldBindJSON(&table); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.CreateTable(&table); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Table created successfully", "table": table})
+}
+
+func (controller *ControllerImplementation) UpdateTable(c *gin.Context) {
+	var table models.Table
+	if err := c.ShouldBindJSON(&table); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := controller.Usecases.UpdateTable(&table); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Table updated successfully"})
+}
+
+func (controller *ControllerImplementation) DeleteTable(c *gin.Context) {
+	id := c.Param("id")
+	if err := controller.Usecases.DeleteTable(id); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Table deleted successfully"})
+}
+
+func (controller *ControllerImplementation) GetTableById(c *gin.Context) {
+	id := c.Param("id")
+	table, err := controller.Usecases.GetTableById(id)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Table not found"})
+		return
+	}
+	c.JSON(200, table)
+}
+
+func (controller *ControllerImplementation) GetAllTables(c *gin.Context) {
+	tables, err := controller.Usecases.GetAllTables()
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Tables not found"})
+		return
+	}
+	c.JSON(200, tables)
+}
+
+func (controller *ControllerImplementation) TransferOrder(c *gin.Context) {
+	var body struct {
+		TableNumber int `json:"table_number" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.TransferOrder(c.Param("id"), body.TableNumber, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
+
+func (controller *ControllerImplementation) MergeTables(c *gin.Context) {
+	var body struct {
+		From int `json:"from" binding:"required"`
+		To   int `json:"to" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.MergeTables(body.From, body.To, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, order)
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+// RecordTip records a tip left on an order apart from its payments.
+func (controller *ControllerImplementation) RecordTip(c *gin.Context) {
+	var request models.TipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tip, err := controller.Usecases.RecordTip(c.Param("id"), request, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, tip)
+}
+
+func (controller *ControllerImplementation) GetOrderTips(c *gin.Context) {
+	tips, err := controller.Usecases.GetOrderTips(c.Param("id"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, tips)
+}
+
+// TipPayout shows the tip pools and each employee's payout for the days
+// ?from=YYYY-MM-DD to ?to=YYYY-MM-DD, both today by default.
+func (controller *ControllerImplementation) TipPayout(c *gin.Context) {
+	days := make([]time.Time, 2)
+	for i, name := range []string{"from", "to"} {
+		if date := c.Query(name); date != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", date, time.Local)
+			if err != nil {
+				c.JSON(400, gin.H{"error": name + " must be YYYY-MM-DD"})
+				return
+			}
+			days[i] = parsed
+		}
+	}
+	payout, err := controller.Usecases.TipPayout(days[0], days[1])
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, payout)
+}
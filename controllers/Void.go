@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) VoidOrder(c *gin.Context) {
+	var request models.VoidRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.VoidOrder(c.Param("id"), request, claim.ID, controller.isManager(claim.ID))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	if order.VoidPending() {
+		c.JSON(202, gin.H{"message": "Void is waiting for a manager's approval", "order": order})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Order voided successfully", "order": order})
+}
+
+func (controller *ControllerImplementation) ApproveVoid(c *gin.Context) {
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.ApproveVoid(c.Param("id"), claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Order voided successfully", "order": order})
+}
+
+func (controller *ControllerImplementation) RejectVoid(c *gin.Context) {
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := controller.Usecases.RejectVoid(c.Param("id"), claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Void rejected", "order": order})
+}
+
+func (controller *ControllerImplementation) GetPendingVoids(c *gin.Context) {
+	orders, err := controller.Usecases.GetPendingVoids()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, orders)
+}
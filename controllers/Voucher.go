@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) IssueVoucher(c *gin.Context) {
+	var voucher models.Voucher
+	if err := c.ShouldBindJSON(&voucher); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := controller.Usecases.IssueVoucher(&voucher, claim.ID); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Voucher issued successfully", "voucher": voucher})
+}
+
+func (controller *ControllerImplementation) GetVoucher(c *gin.Context) {
+	voucher, err := controller.Usecases.GetVoucher(c.Param("code"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, voucher)
+}
+
+func (controller *ControllerImplementation) GetVoucherLedger(c *gin.Context) {
+	entries, err := controller.Usecases.GetVoucherLedger(c.Param("code"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, entries)
+}
+
+func (controller *ControllerImplementation) GetVoucherQRCode(c *gin.Context) {
+	code, err := controller.Usecases.VoucherQRCode(c.Param("code"))
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.Data(200, "image/png", code)
+}
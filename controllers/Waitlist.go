@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+func (controller *ControllerImplementation) JoinWaitlist(c *gin.Context) {
+	var entry models.WaitlistEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := controller.Usecases.JoinWaitlist(&entry, claim.ID); err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Party added to the waitlist", "entry": entry})
+}
+
+// GetWaitlist lists the waiting parties in queue order with their current
+// estimated wait in minutes; -1 means no table can seat the party.
+func (controller *ControllerImplementation) GetWaitlist(c *gin.Context) {
+	queue, err := controller.Usecases.GetWaitlist()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, queue)
+}
+
+func (controller *ControllerImplementation) SeatWaitlistParty(c *gin.Context) {
+	var body struct {
+		TableNumber int `json:"table_number" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	entry, order, err := controller.Usecases.SeatWaitlistParty(c.Param("id"), body.TableNumber, claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"entry": entry, "order": order})
+}
+
+func (controller *ControllerImplementation) WalkAwayWaitlistParty(c *gin.Context) {
+	claim, err := token_services.GetClaims(c)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	entry, err := controller.Usecases.WalkAwayWaitlistParty(c.Param("id"), claim.ID)
+	if err != nil {
+		controller.orderError(c, err)
+		return
+	}
+	c.JSON(200, entry)
+}
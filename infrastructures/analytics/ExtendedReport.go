@@ -30,6 +30,7 @@ type DrinkOrder struct {
 type Order struct {
 	Id         primitive.ObjectID `json:"id" bson:"_id"`
 	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	CustomerId primitive.ObjectID `json:"customer_id,omitempty" bson:"customer_id,omitempty"`
 	Foods      []FoodOrder        `json:"foods" bson:"foods"`
 	Drinks     []DrinkOrder       `json:"drinks" bson:"drinks"`
 
@@ -144,6 +145,16 @@ type CohortData struct {
 	AverageOrderValue float64 `json:"average_order_value"`
 }
 
+// CustomerCohort groups customers by the month of their first order in the
+// period and follows how many of them ordered again in a later month.
+type CustomerCohort struct {
+	CohortLabel        string  `json:"cohort_label"`
+	Customers          int     `json:"customers"`
+	ReturningCustomers int     `json:"returning_customers"`
+	RetentionRate      float64 `json:"retention_rate"`
+	TotalRevenue       float64 `json:"total_revenue"`
+}
+
 type EmployeeEfficiency struct {
 	EmployeeID      string  `json:"employee_id"`
 	EfficiencyRatio float64 `json:"efficiency_ratio"` // orders per checkin
@@ -155,6 +166,7 @@ type ExtendedReport struct {
 	SalesTrend                SalesTrend           `json:"sales_trend"`
 	PredictiveRevenue         float64              `json:"predictive_revenue"`
 	CohortAnalysis            []CohortData         `json:"cohort_analysis"`
+	CustomerCohorts           []CustomerCohort     `json:"customer_cohorts"`
 	EmployeeEfficiencyRanking []EmployeeEfficiency `json:"employee_efficiency_ranking"`
 	EmployeeRevenueRanking    []EmployeeMetrics    `json:"employee_revenue_ranking"`
 }
@@ -549,6 +561,59 @@ func calculateCohortAnalysis(orders []Order) []CohortData {
 	return cohorts
 }
 
+// calculateCustomerCohorts puts every customer in the cohort of the month of
+// their first order and counts those who came back in a later month. Orders
+// without a customer are left out.
+func calculateCustomerCohorts(orders []Order) []CustomerCohort {
+	firstMonth := make(map[primitive.ObjectID]time.Time)
+	for _, order := range orders {
+		if order.CustomerId.IsZero() {
+			continue
+		}
+		month := time.Date(order.CreatedAt.Year(), order.CreatedAt.Month(), 1, 0, 0, 0, 0, order.CreatedAt.Location())
+		if first, ok := firstMonth[order.CustomerId]; !ok || month.Before(first) {
+			firstMonth[order.CustomerId] = month
+		}
+	}
+
+	cohortMap := make(map[time.Time]*CustomerCohort)
+	returned := make(map[primitive.ObjectID]bool)
+	for _, order := range orders {
+		first, ok := firstMonth[order.CustomerId]
+		if !ok {
+			continue
+		}
+		cohort, ok := cohortMap[first]
+		if !ok {
+			cohort = &CustomerCohort{CohortLabel: first.Format("Jan 2006")}
+			cohortMap[first] = cohort
+		}
+		cohort.TotalRevenue += order.TotalPrice
+		if order.CreatedAt.Year() != first.Year() || order.CreatedAt.Month() != first.Month() {
+			returned[order.CustomerId] = true
+		}
+	}
+	for customer, first := range firstMonth {
+		cohortMap[first].Customers++
+		if returned[customer] {
+			cohortMap[first].ReturningCustomers++
+		}
+	}
+
+	months := make([]time.Time, 0, len(cohortMap))
+	for month := range cohortMap {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	cohorts := make([]CustomerCohort, 0, len(months))
+	for _, month := range months {
+		cohort := *cohortMap[month]
+		cohort.RetentionRate = float64(cohort.ReturningCustomers) / float64(cohort.Customers)
+		cohorts = append(cohorts, cohort)
+	}
+	return cohorts
+}
+
 // calculateEmployeeEfficiency computes orders per checkin for each employee.
 func calculateEmployeeEfficiency(empMetrics []EmployeeMetrics, attMetrics AttendanceMetrics) []EmployeeEfficiency {
 	var rankings []EmployeeEfficiency
@@ -645,6 +710,7 @@ func GenerateExtendedReport(db map[string]mongo.Collection, startDate, endDate t
 	salesTrend := calculateSalesTrend(orders)
 	predictiveRevenue := forecastRevenue(salesTrend)
 	cohortAnalysis := calculateCohortAnalysis(orders)
+	customerCohorts := calculateCustomerCohorts(orders)
 	employeeEfficiencyRanking := calculateEmployeeEfficiency(employeeMetrics, attendanceMetrics)
 	employeeRevenueRanking := calculateEmployeeRevenueRanking(employeeMetrics)
 
@@ -653,6 +719,7 @@ func GenerateExtendedReport(db map[string]mongo.Collection, startDate, endDate t
 		SalesTrend:                salesTrend,
 		PredictiveRevenue:         predictiveRevenue,
 		CohortAnalysis:            cohortAnalysis,
+		CustomerCohorts:           customerCohorts,
 		EmployeeEfficiencyRanking: employeeEfficiencyRanking,
 		EmployeeRevenueRanking:    employeeRevenueRanking,
 	}
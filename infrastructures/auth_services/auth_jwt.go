@@ -90,3 +90,21 @@ func (ac *AuthController) RoleMiddleware(role string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// GuestMiddleware admits holders of a current table token to the guest routes
+// and nowhere else; the claims are stored under "guest" for the handlers.
+func (ac *AuthController) GuestMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := token_services.GetGuestClaims(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid guest token"})
+			return
+		}
+		if err := ac.Usecases.CheckGuestToken(claims); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set("guest", claims)
+		c.Next()
+	}
+}
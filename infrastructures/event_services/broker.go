@@ -0,0 +1,51 @@
+package event_services
+
+import (
+	"sync"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// Broker fans order events out to every connected display.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan models.OrderEvent]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[chan models.OrderEvent]struct{}),
+	}
+}
+
+func (b *Broker) Subscribe() chan models.OrderEvent {
+	ch := make(chan models.OrderEvent, 64)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *Broker) Unsubscribe(ch chan models.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Publish never blocks. A subscriber that has fallen behind is dropped; its
+// channel is closed so the client reconnects and catches up from its cursor.
+func (b *Broker) Publish(event models.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
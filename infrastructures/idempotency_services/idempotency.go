@@ -0,0 +1,124 @@
+package idempotency_services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+	"github.com/yesetoda/kushena/usecases"
+)
+
+// Header is the request header carrying the client's idempotency key.
+const Header = "Idempotency-Key"
+
+type IdempotencyController struct {
+	Usecases usecases.UsecaseInterface
+}
+
+func NewIdempotencyController(usecase usecases.UsecaseInterface) IdempotencyController {
+	return IdempotencyController{
+		Usecases: usecase}
+}
+
+// recorder keeps a copy of the response body as it is written.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+func hash(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Middleware makes POST requests that carry an Idempotency-Key safe to retry:
+// the first request runs, and a repeat of it within the retention window gets
+// the first response back without running again. Keys are scoped to the
+// caller and the route.
+func (ic *IdempotencyController) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := models.IdempotencyRecord{
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hash(body),
+			CreatedAt:   time.Now().UTC(),
+		}
+		scope := ""
+		if guest, ok := c.Get("guest"); ok {
+			// guests share no employee id, keep each guest session's keys apart
+			scope = "guest:" + guest.(*models.GuestClaims).Id
+		} else if claims, err := token_services.GetClaims(c); err == nil {
+			record.EmployeeId = claims.ID
+			scope = claims.ID.Hex()
+		}
+		record.Id = hash([]byte(scope), []byte(record.Method), []byte(record.Path), []byte(key))
+
+		stored, err := ic.Usecases.BeginIdempotentRequest(&record)
+		switch {
+		case errors.Is(err, usecases.ErrIdempotencyMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, usecases.ErrIdempotencyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		case stored != nil:
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				ic.Usecases.FinishIdempotentRequest(record.Id, http.StatusInternalServerError, "", nil)
+				panic(r)
+			}
+		}()
+		rec := &recorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+		ic.Usecases.FinishIdempotentRequest(record.Id, rec.Status(), rec.Header().Get("Content-Type"), rec.body.Bytes())
+	}
+}
@@ -2,27 +2,77 @@ package qr_services
 
 import (
 	"bytes"
+	"errors"
 	"image/png"
+	"net/url"
+	"os"
+	"strconv"
 
 	"github.com/boombuler/barcode"
 	"github.com/boombuler/barcode/qr"
 )
 
 type QRService interface {
-	GenerateQRCode(restaurantID string) ([]byte, error)
+	MenuURL(table int, token string) (string, error)
+	GenerateQRCode(table int, token string) ([]byte, error)
+	GenerateVoucherQRCode(code string) ([]byte, error)
 }
 
 type qrService struct {
+	menuURL string
 }
 
-func NewQRService() QRService {
-	return &qrService{}
+// NewQRService builds table codes that open menuURL, the public menu page of
+// this deployment.
+func NewQRService(menuURL string) QRService {
+	return &qrService{menuURL: menuURL}
 }
 
-func (q *qrService) GenerateQRCode(restaurantID string) ([]byte, error) {
-	url := "https://meal-map-2.vercel.app/restaurant/" + restaurantID + "/menu"
+// NewQRServiceFromEnv reads the menu page address from PUBLIC_MENU_URL.
+func NewQRServiceFromEnv() QRService {
+	return NewQRService(os.Getenv("PUBLIC_MENU_URL"))
+}
+
+// MenuURL returns the address a table's code opens, carrying the guest token
+// that lets the holder order there; table 0 gives the plain menu address.
+func (q *qrService) MenuURL(table int, token string) (string, error) {
+	if q.menuURL == "" {
+		return "", errors.New("PUBLIC_MENU_URL is not set")
+	}
+	u, err := url.Parse(q.menuURL)
+	if err != nil {
+		return "", err
+	}
+	if table != 0 {
+		query := u.Query()
+		query.Set("table", strconv.Itoa(table))
+		if token != "" {
+			query.Set("token", token)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
+func (q *qrService) GenerateQRCode(table int, token string) ([]byte, error) {
+	url, err := q.MenuURL(table, token)
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(url)
+}
+
+// GenerateVoucherQRCode returns a printable code holding a voucher code, for
+// the till to scan when the voucher is redeemed.
+func (q *qrService) GenerateVoucherQRCode(code string) ([]byte, error) {
+	if code == "" {
+		return nil, errors.New("voucher code is empty")
+	}
+	return encodePNG(code)
+}
 
-	qrCode, err := qr.Encode(url, qr.L, qr.Auto)
+func encodePNG(content string) ([]byte, error) {
+	qrCode, err := qr.Encode(content, qr.L, qr.Auto)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,37 @@
+package receipt_services
+
+import "bytes"
+
+// ESC/POS command bytes.
+var (
+	escInit        = []byte{0x1B, 0x40}
+	escAlignLeft   = []byte{0x1B, 0x61, 0x00}
+	escAlignCenter = []byte{0x1B, 0x61, 0x01}
+	escBoldOn      = []byte{0x1B, 0x45, 0x01}
+	escBoldOff     = []byte{0x1B, 0x45, 0x00}
+	escFeed        = []byte{0x1B, 0x64, 0x04}
+	escCut         = []byte{0x1D, 0x56, 0x42, 0x00}
+)
+
+// ESCPOS renders the receipt as a byte stream for an ESC/POS thermal printer.
+func (r *Receipt) ESCPOS(width int) []byte {
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	buf.Write(escAlignCenter)
+	buf.Write(escBoldOn)
+	buf.WriteString(r.Restaurant.Name + "\n")
+	buf.Write(escBoldOff)
+	if r.Restaurant.Address != "" {
+		buf.WriteString(r.Restaurant.Address + "\n")
+	}
+	if r.Restaurant.TaxId != "" {
+		buf.WriteString("Tax ID: " + r.Restaurant.TaxId + "\n")
+	}
+	buf.Write(escAlignLeft)
+	for _, line := range r.Body(width) {
+		buf.WriteString(line + "\n")
+	}
+	buf.Write(escFeed)
+	buf.Write(escCut)
+	return buf.Bytes()
+}
@@ -0,0 +1,72 @@
+package receipt_services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pointsPerMM = 72 / 25.4
+	pdfMargin   = 8.0
+)
+
+// pdfString escapes text for a PDF literal string. Courier only covers ASCII,
+// anything else prints as '?'.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// PDF renders the receipt as a single page PDF as wide as the paper roll.
+func (r *Receipt) PDF(paperMM int) []byte {
+	width := ColumnsFor(paperMM)
+	lines := append(r.Header(width), r.Body(width)...)
+
+	pageWidth := float64(paperMM) * pointsPerMM
+	// Courier glyphs are 0.6 em wide
+	fontSize := (pageWidth - 2*pdfMargin) / (float64(width) * 0.6)
+	leading := fontSize * 1.2
+	pageHeight := 2*pdfMargin + float64(len(lines))*leading
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT /F1 %.2f Tf %.2f TL %.2f %.2f Td\n", fontSize, leading, pdfMargin, pageHeight-pdfMargin-fontSize)
+	for _, line := range lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfString(line))
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,92 @@
+package receipt_services
+
+import (
+	"os"
+	"time"
+)
+
+// Restaurant is the header printed on every receipt. It is read from the
+// RESTAURANT_NAME, RESTAURANT_ADDRESS and RESTAURANT_TAX_ID variables.
+type Restaurant struct {
+	Name    string
+	Address string
+	TaxId   string
+}
+
+func RestaurantFromEnv() Restaurant {
+	name := os.Getenv("RESTAURANT_NAME")
+	if name == "" {
+		name = "Kushena"
+	}
+	return Restaurant{
+		Name:    name,
+		Address: os.Getenv("RESTAURANT_ADDRESS"),
+		TaxId:   os.Getenv("RESTAURANT_TAX_ID"),
+	}
+}
+
+type Line struct {
+	Name     string
+	Details  []string
+	Quantity float64
+	Price    float64
+	Total    float64
+}
+
+type TaxLine struct {
+	Name   string
+	Rate   float64
+	Amount float64
+}
+
+type PaymentLine struct {
+	Method   string
+	Amount   float64
+	Tendered float64
+	Change   float64
+}
+
+// Receipt holds everything printed on a guest's bill.
+type Receipt struct {
+	Restaurant    Restaurant
+	Number        int64
+	IssuedAt      time.Time
+	OrderType     string
+	TableNumber   int
+	PickupAt      time.Time
+	DeliverTo     string
+	Lines         []Line
+	Subtotal      float64
+	Discount      float64
+	Taxes         []TaxLine
+	TaxInclusive  bool
+	ServiceCharge float64
+	DeliveryFee   float64
+	Total         float64
+	Payments      []PaymentLine
+
+	// Location is the restaurant's timezone, in which times are printed.
+	Location *time.Location
+}
+
+// local places t in the restaurant's timezone, the server's when unset.
+func (r *Receipt) local(t time.Time) time.Time {
+	if r.Location == nil {
+		return t.Local()
+	}
+	return t.In(r.Location)
+}
+
+// Paper widths in characters for the supported thermal rolls.
+const (
+	Width58mm = 32
+	Width80mm = 48
+)
+
+// ColumnsFor returns the character width for a paper size in millimetres.
+func ColumnsFor(paperMM int) int {
+	if paperMM == 58 {
+		return Width58mm
+	}
+	return Width80mm
+}
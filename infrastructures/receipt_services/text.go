@@ -0,0 +1,100 @@
+package receipt_services
+
+import (
+	"fmt"
+	"strings"
+)
+
+func center(text string, width int) string {
+	if len(text) >= width {
+		return text[:width]
+	}
+	return strings.Repeat(" ", (width-len(text))/2) + text
+}
+
+// columns puts left and right on one line, cutting left short if needed.
+func columns(left, right string, width int) string {
+	room := width - len(right) - 1
+	if room < 1 {
+		return right
+	}
+	if len(left) > room {
+		left = left[:room]
+	}
+	return left + strings.Repeat(" ", width-len(left)-len(right)) + right
+}
+
+func money(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+// Header returns the centred restaurant lines that open a receipt.
+func (r *Receipt) Header(width int) []string {
+	lines := []string{center(r.Restaurant.Name, width)}
+	if r.Restaurant.Address != "" {
+		lines = append(lines, center(r.Restaurant.Address, width))
+	}
+	if r.Restaurant.TaxId != "" {
+		lines = append(lines, center("Tax ID: "+r.Restaurant.TaxId, width))
+	}
+	return lines
+}
+
+// Body returns the receipt below the header, one entry per printed line.
+func (r *Receipt) Body(width int) []string {
+	rule := strings.Repeat("-", width)
+	lines := []string{
+		rule,
+		columns(fmt.Sprintf("Receipt #%06d", r.Number), r.local(r.IssuedAt).Format("2006-01-02 15:04"), width),
+	}
+	if r.TableNumber != 0 {
+		lines = append(lines, fmt.Sprintf("Table %d", r.TableNumber))
+	}
+	switch r.OrderType {
+	case "takeaway":
+		lines = append(lines, "Takeaway, pickup "+r.local(r.PickupAt).Format("15:04"))
+	case "delivery":
+		lines = append(lines, "Delivery to "+r.DeliverTo)
+	}
+	lines = append(lines, rule)
+	for _, line := range r.Lines {
+		lines = append(lines, columns(line.Name, money(line.Total), width))
+		for _, detail := range line.Details {
+			lines = append(lines, "  + "+detail)
+		}
+		lines = append(lines, fmt.Sprintf("  %g x %s", line.Quantity, money(line.Price)))
+	}
+	lines = append(lines, rule, columns("Subtotal", money(r.Subtotal), width))
+	if r.Discount != 0 {
+		lines = append(lines, columns("Discount", "-"+money(r.Discount), width))
+	}
+	for _, tax := range r.Taxes {
+		label := fmt.Sprintf("%s %g%%", tax.Name, tax.Rate)
+		if r.TaxInclusive {
+			label += " (incl.)"
+		}
+		lines = append(lines, columns(label, money(tax.Amount), width))
+	}
+	if r.ServiceCharge != 0 {
+		lines = append(lines, columns("Service charge", money(r.ServiceCharge), width))
+	}
+	if r.DeliveryFee != 0 {
+		lines = append(lines, columns("Delivery fee", money(r.DeliveryFee), width))
+	}
+	lines = append(lines, columns("TOTAL", money(r.Total), width), rule)
+	for _, payment := range r.Payments {
+		lines = append(lines, columns("Paid ("+payment.Method+")", money(payment.Amount), width))
+		if payment.Change != 0 {
+			lines = append(lines, columns("  Tendered", money(payment.Tendered), width))
+			lines = append(lines, columns("  Change", money(payment.Change), width))
+		}
+	}
+	lines = append(lines, "", center("Thank you!", width))
+	return lines
+}
+
+// Text renders the receipt as plain text for a roll width characters wide.
+func (r *Receipt) Text(width int) []byte {
+	lines := append(r.Header(width), r.Body(width)...)
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,82 @@
+package token_services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// GuestTokenLifetime bounds how long a table token works even if the table is
+// never rotated.
+const GuestTokenLifetime = 4 * time.Hour
+
+// guestSecret derives the guest signing key from JWT_SECRET so a guest token
+// never verifies as an employee token and the other way round.
+func guestSecret() []byte {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	mac.Write([]byte("kushena guest table token"))
+	return mac.Sum(nil)
+}
+
+// GenerateGuestToken signs a token that lets its holder order at table while
+// the table stays at generation.
+func GenerateGuestToken(table int, generation int64) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(GuestTokenLifetime)
+	claims := &models.GuestClaims{
+		Table:      table,
+		Generation: generation,
+		StandardClaims: jwt.StandardClaims{
+			Id:        primitive.NewObjectID().Hex(),
+			Audience:  models.GuestAudience,
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  now.Unix(),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(guestSecret())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tokenString, expiresAt, nil
+}
+
+// GetGuestClaims reads a guest token from the Authorization header or, for
+// links opened from a QR code, the token query parameter.
+func GetGuestClaims(c *gin.Context) (*models.GuestClaims, error) {
+	tokenString := c.Query("token")
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, errors.New("invalid token format")
+		}
+		tokenString = parts[1]
+	}
+	if tokenString == "" {
+		return nil, errors.New("missing guest token")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &models.GuestClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return guestSecret(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*models.GuestClaims)
+	if !ok || !token.Valid || !claims.VerifyAudience(models.GuestAudience, true) || claims.Table <= 0 {
+		return nil, errors.New("invalid guest token")
+	}
+	return claims, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/yesetoda/kushena/controllers"
 	"github.com/yesetoda/kushena/infrastructures/auth_services"
+	"github.com/yesetoda/kushena/infrastructures/idempotency_services"
 	"github.com/yesetoda/kushena/repositories"
 	"github.com/yesetoda/kushena/router"
 	"github.com/yesetoda/kushena/usecases"
@@ -70,7 +71,8 @@ func main() {
 	usecase := usecases.NewUsecase(repo)
 	controller := controllers.NewController(usecase)
 	auth := auth_services.NewAuthController(usecase)
-	router := router.NewGinRoute(controller, auth)
+	idempotency := idempotency_services.NewIdempotencyController(usecase)
+	router := router.NewGinRoute(controller, auth, idempotency)
 
 	router.Run() // This will keep running indefinitely
 }
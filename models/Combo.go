@@ -0,0 +1,58 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ComboSlot is one choice in a combo, e.g. "side", filled by one of its foods
+// or drinks.
+type ComboSlot struct {
+	Name     string               `json:"name" bson:"name"`
+	FoodIds  []primitive.ObjectID `json:"food_ids,omitempty" bson:"food_ids,omitempty"`
+	DrinkIds []primitive.ObjectID `json:"drink_ids,omitempty" bson:"drink_ids,omitempty"`
+}
+
+// Combo is a set meal sold at a bundle price, such as burger, fries and a
+// soda. Modifiers chosen on its items are charged on top.
+type Combo struct {
+	Id          primitive.ObjectID `json:"id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	Price       float64            `json:"price" bson:"price"`
+	Category    string             `json:"category" bson:"category"`
+	Description string             `json:"description" bson:"description"`
+	Image       string             `json:"image" bson:"image"`
+	Slots       []ComboSlot        `json:"slots" bson:"slots"`
+}
+
+// ComboChoice is the item picked for one slot of an ordered combo.
+type ComboChoice struct {
+	Slot      string             `json:"slot" bson:"slot"`
+	FoodId    primitive.ObjectID `json:"food_id,omitempty" bson:"food_id,omitempty"`
+	DrinkId   primitive.ObjectID `json:"drink_id,omitempty" bson:"drink_id,omitempty"`
+	Variant   string             `json:"variant,omitempty" bson:"variant,omitempty"`
+	Modifiers []ChosenModifier   `json:"modifiers,omitempty" bson:"modifiers,omitempty"`
+}
+
+// ComboOrder is a combo on an order. Pricing expands it into one food or
+// drink line per choice, which the kitchen and bar work from and which share
+// the bundle price in proportion to their menu prices.
+type ComboOrder struct {
+	LineId     primitive.ObjectID `json:"line_id" bson:"line_id"`
+	ComboId    primitive.ObjectID `json:"combo_id" bson:"combo_id"`
+	ComboName  string             `json:"combo_name" bson:"combo_name"`
+	Price      float64            `json:"price" bson:"price"`
+	Quantity   float64            `json:"quantity" bson:"quantity"`
+	TotalPrice float64            `json:"total_price" bson:"total_price"`
+	Seat       int                `json:"seat,omitempty" bson:"seat,omitempty"`
+	Course     int                `json:"course,omitempty" bson:"course,omitempty"`
+	Held       bool               `json:"held,omitempty" bson:"held,omitempty"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	Choices    []ComboChoice      `json:"choices" bson:"choices"`
+}
+
+// ComboPart marks an order line that was expanded from a combo.
+type ComboPart struct {
+	LineId    primitive.ObjectID `json:"line_id" bson:"line_id"`
+	ComboId   primitive.ObjectID `json:"combo_id" bson:"combo_id"`
+	ComboName string             `json:"combo_name" bson:"combo_name"`
+	Slot      string             `json:"slot" bson:"slot"`
+	ListPrice float64            `json:"list_price" bson:"list_price"`
+}
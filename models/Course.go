@@ -0,0 +1,73 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Usual course numbers. Any positive number may be used; 0 means the line is
+// not coursed and goes to the kitchen straight away.
+const (
+	CourseStarter = 1
+	CourseMain    = 2
+	CourseDessert = 3
+)
+
+// Course states, derived from the lines of a course.
+const (
+	CourseHeld   = "held"
+	CourseFired  = "fired"
+	CourseServed = "served"
+)
+
+// CourseLine is one line of a course as shown in the course view.
+type CourseLine struct {
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Quantity float64   `json:"quantity"`
+	Held     bool      `json:"held"`
+	FiredAt  time.Time `json:"fired_at,omitempty"`
+	ServedAt time.Time `json:"served_at,omitempty"`
+}
+
+// CourseView groups an order's lines by course. A course is held while any of
+// its lines is held, served once all of them are served, and fired otherwise.
+type CourseView struct {
+	Course int          `json:"course"`
+	State  string       `json:"state"`
+	Lines  []CourseLine `json:"lines"`
+}
+
+// Courses returns the order's courses in course order.
+func (order *Order) Courses() []CourseView {
+	byCourse := make(map[int][]CourseLine)
+	for _, food := range order.Foods {
+		byCourse[food.Course] = append(byCourse[food.Course], CourseLine{
+			Kind: "food", Name: food.FoodName, Quantity: food.Quantity,
+			Held: food.Held, FiredAt: food.FiredAt, ServedAt: food.ServedAt,
+		})
+	}
+	for _, drink := range order.Drinks {
+		byCourse[drink.Course] = append(byCourse[drink.Course], CourseLine{
+			Kind: "drink", Name: drink.DrinkName, Quantity: drink.Quantity,
+			Held: drink.Held, FiredAt: drink.FiredAt, ServedAt: drink.ServedAt,
+		})
+	}
+
+	courses := []CourseView{}
+	for course, lines := range byCourse {
+		state := CourseServed
+		for _, line := range lines {
+			if line.Held {
+				state = CourseHeld
+				break
+			}
+			if line.ServedAt.IsZero() {
+				state = CourseFired
+			}
+		}
+		courses = append(courses, CourseView{Course: course, State: state, Lines: lines})
+	}
+	sort.Slice(courses, func(i, j int) bool { return courses[i].Course < courses[j].Course })
+	return courses
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Customer is a guest known by phone number. Visits, spend and points are
+// kept up to date from the payments taken on the customer's orders.
+type Customer struct {
+	Id       primitive.ObjectID `json:"id" bson:"_id"`
+	Phone    string             `json:"phone" bson:"phone"`
+	Name     string             `json:"name" bson:"name"`
+	Birthday time.Time          `json:"birthday,omitempty" bson:"birthday,omitempty"`
+
+	Visits        int       `json:"visits" bson:"visits"`
+	LifetimeSpend float64   `json:"lifetime_spend" bson:"lifetime_spend"`
+	Points        int       `json:"points" bson:"points"`
+	FirstVisitAt  time.Time `json:"first_visit_at,omitempty" bson:"first_visit_at,omitempty"`
+	LastVisitAt   time.Time `json:"last_visit_at,omitempty" bson:"last_visit_at,omitempty"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Loyalty ledger entry kinds.
+const (
+	PointsEarned   = "earned"
+	PointsRedeemed = "redeemed"
+	PointsReversed = "reversed"
+	PointsReturned = "returned"
+)
+
+// LoyaltyEntry is one movement of a customer's points balance. Points is
+// positive when points are added and negative when they are taken away.
+type LoyaltyEntry struct {
+	Id         primitive.ObjectID `json:"id" bson:"_id"`
+	CustomerId primitive.ObjectID `json:"customer_id" bson:"customer_id"`
+	OrderId    primitive.ObjectID `json:"order_id" bson:"order_id"`
+	PaymentId  primitive.ObjectID `json:"payment_id" bson:"payment_id"`
+	Kind       string             `json:"kind" bson:"kind"`
+	Points     int                `json:"points" bson:"points"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	At         time.Time          `json:"at" bson:"at"`
+}
+
+// LoyaltySettings are the rules of the points program. Customers earn
+// EarnRate points for every unit of currency they pay, and spend points at
+// PointValue each once they hold at least MinRedeemPoints. The program is off
+// while both rates are zero.
+type LoyaltySettings struct {
+	EarnRate        float64 `json:"earn_rate" bson:"earn_rate"`
+	PointValue      float64 `json:"point_value" bson:"point_value"`
+	MinRedeemPoints int     `json:"min_redeem_points" bson:"min_redeem_points"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Discount kinds.
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Discount lowers the price of a line or of a whole order. Value is a
+// percentage for percent discounts and an amount for fixed ones.
+type Discount struct {
+	Type   string  `json:"type" bson:"type"`
+	Value  float64 `json:"value" bson:"value"`
+	Reason string  `json:"reason" bson:"reason"`
+}
+
+// TaxBreakdown is the tax charged at one rate on an order.
+type TaxBreakdown struct {
+	Name    string  `json:"name" bson:"name"`
+	Rate    float64 `json:"rate" bson:"rate"`
+	Taxable float64 `json:"taxable" bson:"taxable"`
+	Amount  float64 `json:"amount" bson:"amount"`
+}
@@ -9,4 +9,10 @@ type Drink struct {
 	Category    string             `json:"category" bson:"category"`
 	Description string             `json:"description" bson:"description"`
 	Image       string             `json:"image" bson:"image"`
+
+	ModifierGroups []ModifierGroup `json:"modifier_groups" bson:"modifier_groups"`
+	Variants       []Variant       `json:"variants" bson:"variants"`
+
+	// SoldOut marks an item the kitchen or bar has run out of ("86'd").
+	SoldOut bool `json:"sold_out" bson:"sold_out"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Order sources.
+const (
+	SourceStaff = "staff"
+	SourceGuest = "guest"
+)
+
+// GuestAudience marks a token as a guest's; employee routes never accept it.
+const GuestAudience = "guest"
+
+// GuestClaims is what a guest token carries. Generation must match the table's
+// current token generation, so rotating the table cuts off older tokens; the
+// standard Id names the guest session the orders are grouped under.
+type GuestClaims struct {
+	Table      int   `json:"table"`
+	Generation int64 `json:"generation"`
+	jwt.StandardClaims
+}
+
+// GuestToken is handed to waiters to show or print for a table.
+type GuestToken struct {
+	Table     int       `json:"table"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Idempotency record states.
+const (
+	IdempotencyInProgress = "in_progress"
+	IdempotencyDone       = "done"
+)
+
+// IdempotencyRetention is how long a key and its response are kept; Mongo's
+// TTL monitor removes them afterwards.
+const IdempotencyRetention = 24 * time.Hour
+
+// IdempotencyRecord remembers the response to a request sent with an
+// Idempotency-Key so a retry gets the same response instead of a second write.
+// Id is derived from the caller, method, path and key.
+type IdempotencyRecord struct {
+	Id          string             `json:"id" bson:"_id"`
+	Key         string             `json:"key" bson:"key"`
+	EmployeeId  primitive.ObjectID `json:"employee_id,omitempty" bson:"employee_id,omitempty"`
+	Method      string             `json:"method" bson:"method"`
+	Path        string             `json:"path" bson:"path"`
+	RequestHash string             `json:"request_hash" bson:"request_hash"`
+	State       string             `json:"state" bson:"state"`
+	StatusCode  int                `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	ContentType string             `json:"content_type,omitempty" bson:"content_type,omitempty"`
+	Body        []byte             `json:"body,omitempty" bson:"body,omitempty"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+}
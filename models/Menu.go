@@ -0,0 +1,28 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// MenuItem is a food or drink as guests see it on the public menu.
+type MenuItem struct {
+	Id             primitive.ObjectID `json:"id"`
+	Kind           string             `json:"kind"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	Image          string             `json:"image"`
+	Price          float64            `json:"price"`
+	ModifierGroups []ModifierGroup    `json:"modifier_groups,omitempty"`
+	Variants       []Variant          `json:"variants,omitempty"`
+	Slots          []ComboSlot        `json:"slots,omitempty"`
+}
+
+type MenuCategory struct {
+	Name  string     `json:"name"`
+	Items []MenuItem `json:"items"`
+}
+
+// Menu is the public menu, grouped by category.
+type Menu struct {
+	Restaurant   string         `json:"restaurant"`
+	TaxInclusive bool           `json:"tax_inclusive"`
+	Categories   []MenuCategory `json:"categories"`
+}
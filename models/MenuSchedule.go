@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MenuSchedule offers categories or chosen items only on some days of the
+// week between Start and End, in "HH:MM" restaurant time, such as breakfast
+// until 11:00. An item covered by active schedules is on offer while any of
+// them is; items no schedule covers are always on offer. Days and windows past
+// midnight work as on PricingRule.
+type MenuSchedule struct {
+	Id         primitive.ObjectID   `json:"id" bson:"_id"`
+	Name       string               `json:"name" bson:"name"`
+	Active     bool                 `json:"active" bson:"active"`
+	Categories []string             `json:"categories,omitempty" bson:"categories,omitempty"`
+	FoodIds    []primitive.ObjectID `json:"food_ids,omitempty" bson:"food_ids,omitempty"`
+	DrinkIds   []primitive.ObjectID `json:"drink_ids,omitempty" bson:"drink_ids,omitempty"`
+	Weekdays   []string             `json:"weekdays,omitempty" bson:"weekdays,omitempty"`
+	Start      string               `json:"start" bson:"start"`
+	End        string               `json:"end" bson:"end"`
+
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Availability is the body of the sold-out toggle.
+type Availability struct {
+	SoldOut bool `json:"sold_out"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// ModifierOption is one choice in a modifier group, e.g. "no onions" or
+// "extra cheese", with what it adds to the item price.
+type ModifierOption struct {
+	Name       string  `json:"name" bson:"name"`
+	PriceDelta float64 `json:"price_delta" bson:"price_delta"`
+}
+
+// ModifierGroup is a set of options a guest picks from when ordering an item.
+// MaxChoices of 0 means no upper limit.
+type ModifierGroup struct {
+	Name       string           `json:"name" bson:"name"`
+	Required   bool             `json:"required" bson:"required"`
+	MinChoices int              `json:"min_choices" bson:"min_choices"`
+	MaxChoices int              `json:"max_choices" bson:"max_choices"`
+	Options    []ModifierOption `json:"options" bson:"options"`
+}
+
+// Variant is a size or serving of a drink with its own price.
+type Variant struct {
+	Name  string  `json:"name" bson:"name"`
+	Price float64 `json:"price" bson:"price"`
+}
+
+// ChosenModifier is a modifier picked on an order line. PriceDelta is copied
+// from the menu when the order is priced.
+type ChosenModifier struct {
+	Group      string  `json:"group" bson:"group"`
+	Option     string  `json:"option" bson:"option"`
+	PriceDelta float64 `json:"price_delta" bson:"price_delta"`
+}
@@ -6,12 +6,69 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Order lifecycle states.
+const (
+	OrderNeedsConfirmation = "needs_confirmation"
+	OrderPending           = "pending"
+	OrderAccepted          = "accepted"
+	OrderPreparing         = "preparing"
+	OrderReady             = "ready"
+	OrderServed            = "served"
+	OrderPaid              = "paid"
+	OrderCancelled         = "cancelled"
+	OrderVoided            = "voided"
+	OrderMerged            = "merged"
+)
+
+// ClosedOrderStatuses are the states in which an order no longer changes.
+var ClosedOrderStatuses = []string{OrderPaid, OrderCancelled, OrderVoided, OrderMerged}
+
+// OrderTransitions lists the states an order may move to from each state.
+// Cancelling and voiding need a reason and go through the void flow instead.
+var OrderTransitions = map[string][]string{
+	OrderNeedsConfirmation: {OrderAccepted},
+	OrderPending:           {OrderAccepted},
+	OrderAccepted:          {OrderPreparing},
+	OrderPreparing:         {OrderReady},
+	OrderReady:             {OrderServed},
+	OrderServed:            {OrderPaid},
+	OrderPaid:              {},
+	OrderCancelled:         {},
+	OrderVoided:            {},
+	OrderMerged:            {},
+}
+
 type FoodOrder struct {
 	FoodId     primitive.ObjectID `json:"food_id" bson:"food_id "`
 	FoodName   string             `json:"food_name" bson:"food_name"`
 	Price      float64            `json:"price" bson:"price"`
 	Quantity   float64            `json:"quantity" bson:"quantity"`
 	TotalPrice float64            `json:"total_price" bson:"total_price"`
+	Seat       int                `json:"seat,omitempty" bson:"seat,omitempty"`
+
+	Modifiers []ChosenModifier `json:"modifiers,omitempty" bson:"modifiers,omitempty"`
+	Note      string           `json:"note,omitempty" bson:"note,omitempty"`
+
+	// Course orders the line within the meal; a held line waits for its
+	// course to be fired before the kitchen or bar sees it.
+	Course   int       `json:"course,omitempty" bson:"course,omitempty"`
+	Held     bool      `json:"held,omitempty" bson:"held,omitempty"`
+	FiredAt  time.Time `json:"fired_at,omitempty" bson:"fired_at,omitempty"`
+	ServedAt time.Time `json:"served_at,omitempty" bson:"served_at,omitempty"`
+
+	Category       string    `json:"category" bson:"category"`
+	Discount       *Discount `json:"discount,omitempty" bson:"discount,omitempty"`
+	DiscountAmount float64   `json:"discount_amount" bson:"discount_amount"`
+	TaxRate        float64   `json:"tax_rate" bson:"tax_rate"`
+
+	// AddedAt is when the line went on the order; pricing rules apply as of
+	// then.
+	AddedAt time.Time `json:"added_at,omitempty" bson:"added_at,omitempty"`
+	// PricingRule is the happy hour or special that set Price, if any.
+	PricingRule *AppliedPricingRule `json:"pricing_rule,omitempty" bson:"pricing_rule,omitempty"`
+	// Combo is set on lines expanded from a combo; Price is then the line's
+	// share of the bundle price.
+	Combo *ComboPart `json:"combo,omitempty" bson:"combo,omitempty"`
 }
 
 type DrinkOrder struct {
@@ -20,15 +77,133 @@ type DrinkOrder struct {
 	Price      float64            `json:"price" bson:"price"`
 	Quantity   float64            `json:"quantity" bson:"quantity"`
 	TotalPrice float64            `json:"total_price" bson:"total_price"`
+	Seat       int                `json:"seat,omitempty" bson:"seat,omitempty"`
+
+	Variant   string           `json:"variant,omitempty" bson:"variant,omitempty"`
+	Modifiers []ChosenModifier `json:"modifiers,omitempty" bson:"modifiers,omitempty"`
+	Note      string           `json:"note,omitempty" bson:"note,omitempty"`
+
+	// course and firing work as on FoodOrder
+	Course   int       `json:"course,omitempty" bson:"course,omitempty"`
+	Held     bool      `json:"held,omitempty" bson:"held,omitempty"`
+	FiredAt  time.Time `json:"fired_at,omitempty" bson:"fired_at,omitempty"`
+	ServedAt time.Time `json:"served_at,omitempty" bson:"served_at,omitempty"`
+
+	Category       string    `json:"category" bson:"category"`
+	Discount       *Discount `json:"discount,omitempty" bson:"discount,omitempty"`
+	DiscountAmount float64   `json:"discount_amount" bson:"discount_amount"`
+	TaxRate        float64   `json:"tax_rate" bson:"tax_rate"`
+
+	// AddedAt is when the line went on the order; pricing rules apply as of
+	// then.
+	AddedAt time.Time `json:"added_at,omitempty" bson:"added_at,omitempty"`
+	// PricingRule is the happy hour or special that set Price, if any.
+	PricingRule *AppliedPricingRule `json:"pricing_rule,omitempty" bson:"pricing_rule,omitempty"`
+	// Combo is set on lines expanded from a combo; Price is then the line's
+	// share of the bundle price.
+	Combo *ComboPart `json:"combo,omitempty" bson:"combo,omitempty"`
 }
+
+// StatusChange records one step of an order's lifecycle.
+type StatusChange struct {
+	From       string             `json:"from" bson:"from"`
+	To         string             `json:"to" bson:"to"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	At         time.Time          `json:"at" bson:"at"`
+}
+
 type Order struct {
-	Id          primitive.ObjectID `json:"id" bson:"_id"`
-	EmployeeId  primitive.ObjectID `json:"employee_id" bson:"employee_id"`
-	TableNumber int                `json:"table_number" bson:"table_number"`
-	Foods       []FoodOrder        `json:"foods" bson:"foods"`
-	Drinks      []DrinkOrder       `json:"drinks" bson:"drinks"`
-
-	TotalPrice float64   `json:"total_price" bson:"total_price"`
-	Status     string    `json:"status" bson:"status"`
-	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	Id           primitive.ObjectID `json:"id" bson:"_id"`
+	Version      int64              `json:"version" bson:"version"`
+	EmployeeId   primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	Type         string             `json:"type" bson:"type"`
+	Source       string             `json:"source,omitempty" bson:"source,omitempty"`
+	GuestSession string             `json:"guest_session,omitempty" bson:"guest_session,omitempty"`
+	CustomerId   primitive.ObjectID `json:"customer_id,omitempty" bson:"customer_id,omitempty"`
+	TableNumber  int                `json:"table_number" bson:"table_number"`
+	PickupAt     time.Time          `json:"pickup_at,omitempty" bson:"pickup_at,omitempty"`
+	Delivery     *Delivery          `json:"delivery,omitempty" bson:"delivery,omitempty"`
+	Foods        []FoodOrder        `json:"foods" bson:"foods"`
+	Drinks       []DrinkOrder       `json:"drinks" bson:"drinks"`
+	Combos       []ComboOrder       `json:"combos,omitempty" bson:"combos,omitempty"`
+
+	// Subtotal is the sum of line prices before discounts; NetSales is what
+	// remains after discounts and without tax. TotalPrice is what the guest pays.
+	Subtotal      float64        `json:"subtotal" bson:"subtotal"`
+	Discount      *Discount      `json:"discount,omitempty" bson:"discount,omitempty"`
+	DiscountTotal float64        `json:"discount_total" bson:"discount_total"`
+	NetSales      float64        `json:"net_sales" bson:"net_sales"`
+	Taxes         []TaxBreakdown `json:"taxes" bson:"taxes"`
+	TaxTotal      float64        `json:"tax_total" bson:"tax_total"`
+	TaxInclusive  bool           `json:"tax_inclusive" bson:"tax_inclusive"`
+	ServiceCharge float64        `json:"service_charge" bson:"service_charge"`
+	DeliveryFee   float64        `json:"delivery_fee" bson:"delivery_fee"`
+
+	TotalPrice    float64        `json:"total_price" bson:"total_price"`
+	Status        string         `json:"status" bson:"status"`
+	StatusHistory []StatusChange `json:"status_history" bson:"status_history"`
+	CreatedAt     time.Time      `json:"created_at" bson:"created_at"`
+
+	MergedInto primitive.ObjectID `json:"merged_into,omitempty" bson:"merged_into,omitempty"`
+
+	SplitMode string     `json:"split_mode,omitempty" bson:"split_mode,omitempty"`
+	Splits    []BillPart `json:"splits,omitempty" bson:"splits,omitempty"`
+
+	Void *Void `json:"void,omitempty" bson:"void,omitempty"`
+}
+
+// Unsettled reports whether any part of a split check is still open.
+func (order *Order) Unsettled() bool {
+	for _, part := range order.Splits {
+		if !part.Settled {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSettledPart reports whether any part of a split check was already settled.
+func (order *Order) HasSettledPart() bool {
+	for _, part := range order.Splits {
+		if part.Settled {
+			return true
+		}
+	}
+	return false
+}
+
+// ReachedServed reports whether the order was served at some point, even if
+// it has moved on since.
+func (order *Order) ReachedServed() bool {
+	for _, change := range order.StatusHistory {
+		if change.To == OrderServed {
+			return true
+		}
+	}
+	return order.Status == OrderServed || order.Status == OrderPaid
+}
+
+// VoidPending reports whether a void is waiting for a manager's approval.
+func (order *Order) VoidPending() bool {
+	return order.Void != nil && !order.Void.Approved
+}
+
+// IsClosed reports whether the order is paid, cancelled or merged into another check.
+func (order *Order) IsClosed() bool {
+	for _, status := range ClosedOrderStatuses {
+		if order.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether an order in state from may move to state to.
+func CanTransition(from, to string) bool {
+	for _, next := range OrderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Order event types pushed to the kitchen and bar displays.
+const (
+	EventOrderCreated  = "order_created"
+	EventOrderUpdated  = "order_updated"
+	EventStatusChanged = "status_changed"
+	EventCourseFired   = "course_fired"
+)
+
+// OrderEventRetention is how long events are kept for displays to resume
+// from; Mongo's TTL monitor removes them afterwards.
+const OrderEventRetention = 24 * time.Hour
+
+// OrderEvent is a snapshot of an order at the time something happened to it.
+// Seq is strictly increasing and is used as the resume cursor by display clients.
+type OrderEvent struct {
+	Id      primitive.ObjectID `json:"id" bson:"_id"`
+	Seq     int64              `json:"seq" bson:"seq"`
+	Type    string             `json:"type" bson:"type"`
+	OrderId primitive.ObjectID `json:"order_id" bson:"order_id"`
+	Order   Order              `json:"order" bson:"order"`
+	At      time.Time          `json:"at" bson:"at"`
+}
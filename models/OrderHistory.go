@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldChange is one field of an order going from one value to another.
+type FieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	From  interface{} `json:"from" bson:"from"`
+	To    interface{} `json:"to" bson:"to"`
+}
+
+// OrderChange is an append-only record of who changed an order, when, and how.
+// Version is the order version the change produced.
+type OrderChange struct {
+	Id         primitive.ObjectID `json:"id" bson:"_id"`
+	OrderId    primitive.ObjectID `json:"order_id" bson:"order_id"`
+	Version    int64              `json:"version" bson:"version"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	At         time.Time          `json:"at" bson:"at"`
+	Changes    []FieldChange      `json:"changes" bson:"changes"`
+}
+
+// OrderPatch is a partial order update. Only the fields that are present are
+// changed; Version must match the stored order or the update is rejected.
+type OrderPatch struct {
+	Id             primitive.ObjectID `json:"id" binding:"required"`
+	Version        int64              `json:"version"`
+	Foods          *[]FoodOrder       `json:"foods"`
+	Drinks         *[]DrinkOrder      `json:"drinks"`
+	Combos         *[]ComboOrder      `json:"combos"`
+	Discount       *Discount          `json:"discount"`
+	RemoveDiscount bool               `json:"remove_discount"`
+}
+
+// TrackedFields returns the order fields whose changes go into the history.
+func (order *Order) TrackedFields() map[string]interface{} {
+	return map[string]interface{}{
+		"table_number": order.TableNumber,
+		"foods":        order.Foods,
+		"drinks":       order.Drinks,
+		"combos":       order.Combos,
+		"discount":     order.Discount,
+		"total_price":  order.TotalPrice,
+		"status":       order.Status,
+		"splits":       order.Splits,
+		"merged_into":  order.MergedInto,
+		"void":         order.Void,
+		"delivery":     order.Delivery,
+		"customer_id":  order.CustomerId,
+	}
+}
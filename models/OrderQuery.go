@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Order listing sort keys; a leading "-" sorts descending.
+const (
+	SortNewest     = "-created_at"
+	SortOldest     = "created_at"
+	SortTotalDesc  = "-total_price"
+	SortTotalAsc   = "total_price"
+	DefaultPerPage = 50
+	MaxPerPage     = 200
+)
+
+// OrderQuery filters and pages an order listing. Zero values do not filter.
+type OrderQuery struct {
+	From        time.Time
+	To          time.Time
+	Statuses    []string
+	Type        string
+	TableNumber int
+	EmployeeId  primitive.ObjectID
+	MinTotal    *float64
+	MaxTotal    *float64
+	ItemId      primitive.ObjectID
+	Sort        string
+	Limit       int
+	Cursor      string
+}
+
+// OrderPage is one page of an order listing. NextCursor is empty on the last page.
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
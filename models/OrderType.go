@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Order types.
+const (
+	OrderDineIn   = "dine_in"
+	OrderTakeaway = "takeaway"
+	OrderDelivery = "delivery"
+)
+
+// ValidOrderType reports whether orderType is one of the order types.
+func ValidOrderType(orderType string) bool {
+	switch orderType {
+	case OrderDineIn, OrderTakeaway, OrderDelivery:
+		return true
+	}
+	return false
+}
+
+// Delivery holds where a delivery order goes, when it was promised and who
+// drives it.
+type Delivery struct {
+	CustomerName string             `json:"customer_name" bson:"customer_name"`
+	Phone        string             `json:"phone" bson:"phone"`
+	Address      string             `json:"address" bson:"address"`
+	Fee          float64            `json:"fee" bson:"fee"`
+	PromisedAt   time.Time          `json:"promised_at" bson:"promised_at"`
+	DriverId     primitive.ObjectID `json:"driver_id,omitempty" bson:"driver_id,omitempty"`
+	AssignedAt   time.Time          `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
+}
+
+// OrderType returns the order's type; orders taken before types existed are
+// dine-in.
+func (order *Order) OrderType() string {
+	if order.Type == "" {
+		return OrderDineIn
+	}
+	return order.Type
+}
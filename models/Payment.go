@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payment methods.
+const (
+	PaymentCash        = "cash"
+	PaymentCard        = "card"
+	PaymentMobileMoney = "mobile_money"
+	PaymentPoints      = "loyalty_points"
+	PaymentVoucher     = "voucher"
+)
+
+// ValidPaymentMethod reports whether method is one of the accepted payment methods.
+func ValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentCash, PaymentCard, PaymentMobileMoney, PaymentPoints, PaymentVoucher:
+		return true
+	}
+	return false
+}
+
+type Refund struct {
+	Amount     float64            `json:"amount" bson:"amount"`
+	Reason     string             `json:"reason" bson:"reason"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	At         time.Time          `json:"at" bson:"at"`
+}
+
+// Payment is money taken against an order, or against one part of a split
+// check when Part is set. Amount is what was applied to the bill and Tip what
+// was left on top of it; for cash, Tendered - Change == Amount + Tip.
+type Payment struct {
+	Id         primitive.ObjectID `json:"id" bson:"_id"`
+	OrderId    primitive.ObjectID `json:"order_id" bson:"order_id"`
+	Part       int                `json:"part,omitempty" bson:"part,omitempty"`
+	Method     string             `json:"method" bson:"method"`
+	Amount     float64            `json:"amount" bson:"amount"`
+	Tendered   float64            `json:"tendered" bson:"tendered"`
+	Change     float64            `json:"change" bson:"change"`
+	Tip        float64            `json:"tip,omitempty" bson:"tip,omitempty"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+
+	// Points paid for a loyalty_points payment; PointsEarned were given to
+	// the order's customer for any other payment.
+	Points       int `json:"points,omitempty" bson:"points,omitempty"`
+	PointsEarned int `json:"points_earned,omitempty" bson:"points_earned,omitempty"`
+	// VoucherId is the voucher a voucher payment was taken from.
+	VoucherId primitive.ObjectID `json:"voucher_id,omitempty" bson:"voucher_id,omitempty"`
+
+	Refunded float64  `json:"refunded" bson:"refunded"`
+	Refunds  []Refund `json:"refunds,omitempty" bson:"refunds,omitempty"`
+}
+
+// Net is the amount kept after refunds.
+func (payment *Payment) Net() float64 {
+	return payment.Amount - payment.Refunded
+}
+
+type PaymentRequest struct {
+	Method   string  `json:"method" binding:"required"`
+	Amount   float64 `json:"amount"`
+	Tendered float64 `json:"tendered"`
+	Part     int     `json:"part"`
+	// Points to spend on a loyalty_points payment.
+	Points int `json:"points"`
+	// VoucherCode is the voucher to take a voucher payment from.
+	VoucherCode string `json:"voucher_code"`
+	// Tip is left on top of Amount and does not count towards the bill.
+	Tip float64 `json:"tip"`
+}
+
+type RefundRequest struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason" binding:"required"`
+}
+
+// CashReconciliation compares the cash a cashier should hold at the end of the
+// day with what was counted in the drawer.
+type CashReconciliation struct {
+	Id         primitive.ObjectID `json:"id" bson:"_id"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	From       time.Time          `json:"from" bson:"from"`
+	To         time.Time          `json:"to" bson:"to"`
+	Float      float64            `json:"float" bson:"float"`
+	CashTaken  float64            `json:"cash_taken" bson:"cash_taken"`
+	CashTips   float64            `json:"cash_tips" bson:"cash_tips"`
+	CashRefund float64            `json:"cash_refunded" bson:"cash_refunded"`
+	Expected   float64            `json:"expected" bson:"expected"`
+	Counted    float64            `json:"counted" bson:"counted"`
+	Difference float64            `json:"difference" bson:"difference"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type ReconciliationRequest struct {
+	Counted float64 `json:"counted"`
+	Float   float64 `json:"float"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Pricing rule kinds. A percent rule takes Value percent off the line's unit
+// price; a fixed price rule charges Value for the item, modifiers on top.
+const (
+	PricingPercent    = "percent"
+	PricingFixedPrice = "fixed_price"
+)
+
+// Weekdays are the day names pricing rules are written with, indexed by
+// time.Weekday.
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// PricingRule changes the price of a category or of chosen items on some days
+// of the week between Start and End, in "HH:MM" restaurant time. A window that
+// ends at or before its start runs past midnight and belongs to the day it
+// starts on. No weekdays means every day.
+type PricingRule struct {
+	Id       primitive.ObjectID   `json:"id" bson:"_id"`
+	Name     string               `json:"name" bson:"name"`
+	Active   bool                 `json:"active" bson:"active"`
+	Category string               `json:"category,omitempty" bson:"category,omitempty"`
+	FoodIds  []primitive.ObjectID `json:"food_ids,omitempty" bson:"food_ids,omitempty"`
+	DrinkIds []primitive.ObjectID `json:"drink_ids,omitempty" bson:"drink_ids,omitempty"`
+	Weekdays []string             `json:"weekdays,omitempty" bson:"weekdays,omitempty"`
+	Start    string               `json:"start" bson:"start"`
+	End      string               `json:"end" bson:"end"`
+	Type     string               `json:"type" bson:"type"`
+	Value    float64              `json:"value" bson:"value"`
+
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// AppliedPricingRule records the rule that priced an order line and the menu
+// price the line would have had without it.
+type AppliedPricingRule struct {
+	RuleId    primitive.ObjectID `json:"rule_id" bson:"rule_id"`
+	Name      string             `json:"name" bson:"name"`
+	ListPrice float64            `json:"list_price" bson:"list_price"`
+}
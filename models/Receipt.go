@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Receipt reserves a receipt number for an order. Numbers come from a gap-free
+// sequence and an order keeps its number when the receipt is printed again.
+type Receipt struct {
+	Number     int64              `json:"number" bson:"_id"`
+	OrderId    primitive.ObjectID `json:"order_id" bson:"order_id"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	IssuedAt   time.Time          `json:"issued_at" bson:"issued_at"`
+}
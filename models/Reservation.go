@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reservation states.
+const (
+	ReservationPending   = "pending"
+	ReservationConfirmed = "confirmed"
+	ReservationSeated    = "seated"
+	ReservationNoShow    = "no_show"
+	ReservationCancelled = "cancelled"
+)
+
+// DefaultReservationMinutes is how long a table is held when no duration is given.
+const DefaultReservationMinutes = 90
+
+// ActiveReservationStatuses are the states in which a reservation holds its table.
+var ActiveReservationStatuses = []string{ReservationPending, ReservationConfirmed, ReservationSeated}
+
+// ReservationTransitions lists the states a reservation may move to from each state.
+var ReservationTransitions = map[string][]string{
+	ReservationPending:   {ReservationConfirmed, ReservationSeated, ReservationNoShow, ReservationCancelled},
+	ReservationConfirmed: {ReservationSeated, ReservationNoShow, ReservationCancelled},
+	ReservationSeated:    {},
+	ReservationNoShow:    {},
+	ReservationCancelled: {},
+}
+
+type Reservation struct {
+	Id              primitive.ObjectID `json:"id" bson:"_id"`
+	GuestName       string             `json:"guest_name" bson:"guest_name"`
+	Phone           string             `json:"phone" bson:"phone"`
+	PartySize       int                `json:"party_size" bson:"party_size"`
+	StartsAt        time.Time          `json:"starts_at" bson:"starts_at"`
+	DurationMinutes int                `json:"duration_minutes" bson:"duration_minutes"`
+	EndsAt          time.Time          `json:"ends_at" bson:"ends_at"`
+	TableNumber     int                `json:"table_number,omitempty" bson:"table_number,omitempty"`
+	Section         string             `json:"section,omitempty" bson:"section,omitempty"`
+	Notes           string             `json:"notes,omitempty" bson:"notes,omitempty"`
+	Status          string             `json:"status" bson:"status"`
+	StatusHistory   []StatusChange     `json:"status_history" bson:"status_history"`
+	CreatedBy       primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// IsActive reports whether the reservation still holds its table.
+func (reservation *Reservation) IsActive() bool {
+	for _, status := range ActiveReservationStatuses {
+		if reservation.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionReservation reports whether a reservation in state from may move to state to.
+func CanTransitionReservation(from, to string) bool {
+	for _, next := range ReservationTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// SettingsId is the _id of the single settings document.
+const SettingsId = "settings"
+
+// Settings holds the restaurant-wide pricing configuration. Rates are percentages.
+type Settings struct {
+	Id                string             `json:"-" bson:"_id"`
+	TaxName           string             `json:"tax_name" bson:"tax_name"`
+	TaxRate           float64            `json:"tax_rate" bson:"tax_rate"`
+	CategoryTaxRates  map[string]float64 `json:"category_tax_rates" bson:"category_tax_rates"`
+	TaxInclusive      bool               `json:"tax_inclusive" bson:"tax_inclusive"`
+	ServiceChargeRate float64            `json:"service_charge_rate" bson:"service_charge_rate"`
+	Loyalty           LoyaltySettings    `json:"loyalty" bson:"loyalty"`
+	TipPool           TipPoolSettings    `json:"tip_pool" bson:"tip_pool"`
+	// Timezone is the IANA name of the restaurant's timezone; empty means the
+	// server's own.
+	Timezone  string    `json:"timezone" bson:"timezone"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// DefaultSettings is used until a manager saves settings: no tax, no service charge.
+func DefaultSettings() *Settings {
+	return &Settings{
+		Id:               SettingsId,
+		TaxName:          "VAT",
+		CategoryTaxRates: map[string]float64{},
+	}
+}
+
+// Location returns the restaurant's timezone, falling back to the server's
+// when none is set or the name is unknown.
+func (settings *Settings) Location() *time.Location {
+	if settings.Timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// TaxRateFor returns the tax rate that applies to a menu category.
+func (settings *Settings) TaxRateFor(category string) float64 {
+	if rate, ok := settings.CategoryTaxRates[category]; ok {
+		return rate
+	}
+	return settings.TaxRate
+}
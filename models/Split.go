@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ways a check can be split.
+const (
+	SplitByItems = "items"
+	SplitBySeat  = "seat"
+	SplitEven    = "even"
+)
+
+// BillPart is one independently settled share of an order's check. Parts live
+// on the order so revenue is still counted once, from the order total.
+type BillPart struct {
+	Number    int                `json:"number" bson:"number"`
+	Seat      int                `json:"seat,omitempty" bson:"seat,omitempty"`
+	Foods     []FoodOrder        `json:"foods,omitempty" bson:"foods,omitempty"`
+	Drinks    []DrinkOrder       `json:"drinks,omitempty" bson:"drinks,omitempty"`
+	Amount    float64            `json:"amount" bson:"amount"`
+	Settled   bool               `json:"settled" bson:"settled"`
+	SettledBy primitive.ObjectID `json:"settled_by,omitempty" bson:"settled_by,omitempty"`
+	SettledAt time.Time          `json:"settled_at,omitempty" bson:"settled_at,omitempty"`
+}
+
+// SplitItems picks order lines by their index in Order.Foods and Order.Drinks.
+type SplitItems struct {
+	Foods  []int `json:"foods"`
+	Drinks []int `json:"drinks"`
+}
+
+type SplitRequest struct {
+	Mode  string       `json:"mode" binding:"required"`
+	Parts int          `json:"parts"`
+	Items []SplitItems `json:"items"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Table states.
+const (
+	TableFree     = "free"
+	TableOccupied = "occupied"
+	TableReserved = "reserved"
+	TableDirty    = "dirty"
+)
+
+type Table struct {
+	Id       primitive.ObjectID `json:"id" bson:"_id"`
+	Number   int                `json:"number" bson:"number"`
+	Capacity int                `json:"capacity" bson:"capacity"`
+	Section  string             `json:"section" bson:"section"`
+	Status   string             `json:"status" bson:"status"`
+
+	// WaiterId is the waiter serving the table; guest orders are theirs.
+	WaiterId primitive.ObjectID `json:"waiter_id,omitempty" bson:"waiter_id,omitempty"`
+	// GuestTokenGeneration goes up every time the table's guest token rotates.
+	GuestTokenGeneration int64 `json:"-" bson:"guest_token_generation"`
+}
+
+// ValidTableStatus reports whether status is one of the table states.
+func ValidTableStatus(status string) bool {
+	switch status {
+	case TableFree, TableOccupied, TableReserved, TableDirty:
+		return true
+	}
+	return false
+}
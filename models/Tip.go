@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tip is money a guest left for the staff on an order. A tip added to a
+// payment carries the payment's id; one left apart from the bill does not.
+type Tip struct {
+	Id         primitive.ObjectID `json:"id" bson:"_id"`
+	OrderId    primitive.ObjectID `json:"order_id" bson:"order_id"`
+	PaymentId  primitive.ObjectID `json:"payment_id,omitempty" bson:"payment_id,omitempty"`
+	Amount     float64            `json:"amount" bson:"amount"`
+	Method     string             `json:"method" bson:"method"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	At         time.Time          `json:"at" bson:"at"`
+}
+
+type TipRequest struct {
+	Amount float64 `json:"amount"`
+	Method string  `json:"method" binding:"required"`
+}
+
+// Tip pool periods.
+const (
+	TipPoolDaily   = "day"
+	TipPoolByShift = "shift"
+)
+
+// TipShift is a named part of the day, as "HH:MM" local times. A shift whose
+// end is not after its start runs past midnight.
+type TipShift struct {
+	Name  string `json:"name" bson:"name"`
+	Start string `json:"start" bson:"start"`
+	End   string `json:"end" bson:"end"`
+}
+
+// TipPoolSettings decide how tips are shared. Tips are pooled per day, or per
+// shift, and each employee gets a share of their pool in proportion to the
+// hours they were checked in during it times the weight of their role. Roles
+// missing from RoleWeights weigh 1.
+type TipPoolSettings struct {
+	Period      string             `json:"period" bson:"period"`
+	Shifts      []TipShift         `json:"shifts,omitempty" bson:"shifts,omitempty"`
+	RoleWeights map[string]float64 `json:"role_weights,omitempty" bson:"role_weights,omitempty"`
+}
+
+// WeightFor returns the tip weight of a role.
+func (pool *TipPoolSettings) WeightFor(role string) float64 {
+	if weight, ok := pool.RoleWeights[role]; ok {
+		return weight
+	}
+	return 1
+}
+
+// TipShare is one employee's part of a tip pool.
+type TipShare struct {
+	EmployeeId primitive.ObjectID `json:"employee_id"`
+	Name       string             `json:"name"`
+	Role       string             `json:"role"`
+	Hours      float64            `json:"hours"`
+	Weight     float64            `json:"weight"`
+	Payout     float64            `json:"payout"`
+}
+
+// TipPool is the tips of one day or shift and how they are paid out.
+// Unallocated is money no one was checked in to receive.
+type TipPool struct {
+	Name        string     `json:"name"`
+	From        time.Time  `json:"from"`
+	To          time.Time  `json:"to"`
+	Tips        float64    `json:"tips"`
+	Unallocated float64    `json:"unallocated"`
+	Shares      []TipShare `json:"shares"`
+}
+
+// TipPayout is the tip pools of a period and each employee's total. Unpooled
+// is money tipped outside every shift.
+type TipPayout struct {
+	From     time.Time  `json:"from"`
+	To       time.Time  `json:"to"`
+	Tips     float64    `json:"tips"`
+	Unpooled float64    `json:"unpooled"`
+	Pools    []TipPool  `json:"pools"`
+	Totals   []TipShare `json:"totals"`
+}
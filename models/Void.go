@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Void reason codes.
+const (
+	VoidCustomerChangedMind = "customer_changed_mind"
+	VoidWrongOrder          = "wrong_order"
+	VoidKitchenError        = "kitchen_error"
+	VoidDuplicate           = "duplicate"
+	VoidWalkout             = "walkout"
+	VoidOther               = "other"
+)
+
+// ValidVoidReason reports whether reason is one of the void reason codes.
+func ValidVoidReason(reason string) bool {
+	switch reason {
+	case VoidCustomerChangedMind, VoidWrongOrder, VoidKitchenError, VoidDuplicate, VoidWalkout, VoidOther:
+		return true
+	}
+	return false
+}
+
+// Void records why and by whom an order was taken off the books. A void asked
+// for after the order was served waits for a manager until Approved is set.
+type Void struct {
+	Reason      string             `json:"reason" bson:"reason"`
+	Note        string             `json:"note,omitempty" bson:"note,omitempty"`
+	RequestedBy primitive.ObjectID `json:"requested_by" bson:"requested_by"`
+	RequestedAt time.Time          `json:"requested_at" bson:"requested_at"`
+	Approved    bool               `json:"approved" bson:"approved"`
+	ApprovedBy  primitive.ObjectID `json:"approved_by,omitempty" bson:"approved_by,omitempty"`
+	ApprovedAt  time.Time          `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+}
+
+type VoidRequest struct {
+	Reason string `json:"reason" binding:"required"`
+	Note   string `json:"note"`
+}
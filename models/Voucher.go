@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultVoucherMonths is how long a voucher is valid when no expiry is given.
+const DefaultVoucherMonths = 12
+
+// Voucher is a prepaid gift card. Balance is what is left of FaceValue after
+// redemptions; it can be spent over several orders until ExpiresAt.
+type Voucher struct {
+	Id        primitive.ObjectID `json:"id" bson:"_id"`
+	Code      string             `json:"code" bson:"code"`
+	FaceValue float64            `json:"face_value" bson:"face_value"`
+	Balance   float64            `json:"balance" bson:"balance"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	Purchaser string             `json:"purchaser,omitempty" bson:"purchaser,omitempty"`
+	Note      string             `json:"note,omitempty" bson:"note,omitempty"`
+	IssuedBy  primitive.ObjectID `json:"issued_by" bson:"issued_by"`
+	IssuedAt  time.Time          `json:"issued_at" bson:"issued_at"`
+}
+
+// Expired reports whether the voucher can no longer be spent at t.
+func (voucher *Voucher) Expired(t time.Time) bool {
+	return !t.Before(voucher.ExpiresAt)
+}
+
+// Voucher ledger entry kinds.
+const (
+	VoucherIssued   = "issued"
+	VoucherRedeemed = "redeemed"
+	VoucherRefunded = "refunded"
+)
+
+// VoucherEntry is one movement of a voucher's balance. Amount is positive when
+// value is added and negative when it is spent; Balance is what was left after.
+type VoucherEntry struct {
+	Id         primitive.ObjectID `json:"id" bson:"_id"`
+	VoucherId  primitive.ObjectID `json:"voucher_id" bson:"voucher_id"`
+	Kind       string             `json:"kind" bson:"kind"`
+	Amount     float64            `json:"amount" bson:"amount"`
+	Balance    float64            `json:"balance" bson:"balance"`
+	OrderId    primitive.ObjectID `json:"order_id,omitempty" bson:"order_id,omitempty"`
+	PaymentId  primitive.ObjectID `json:"payment_id,omitempty" bson:"payment_id,omitempty"`
+	EmployeeId primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+	At         time.Time          `json:"at" bson:"at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Waitlist states.
+const (
+	WaitlistWaiting    = "waiting"
+	WaitlistSeated     = "seated"
+	WaitlistWalkedAway = "walked_away"
+)
+
+// DefaultTurnMinutes is how long a party is expected to hold a table when
+// there is no paid dine-in order to learn from yet.
+const DefaultTurnMinutes = 60
+
+type WaitlistEntry struct {
+	Id        primitive.ObjectID `json:"id" bson:"_id"`
+	Name      string             `json:"name" bson:"name"`
+	PartySize int                `json:"party_size" bson:"party_size"`
+	Phone     string             `json:"phone,omitempty" bson:"phone,omitempty"`
+	Notes     string             `json:"notes,omitempty" bson:"notes,omitempty"`
+	Status    string             `json:"status" bson:"status"`
+	JoinedAt  time.Time          `json:"joined_at" bson:"joined_at"`
+	AddedBy   primitive.ObjectID `json:"added_by" bson:"added_by"`
+
+	// QuotedMinutes is the wait the party was told when it joined.
+	QuotedMinutes int `json:"quoted_minutes" bson:"quoted_minutes"`
+	// EstimatedMinutes is the current estimate; it is worked out on every
+	// read of the queue and not stored.
+	EstimatedMinutes int `json:"estimated_minutes" bson:"-"`
+
+	SeatedAt    time.Time          `json:"seated_at,omitempty" bson:"seated_at,omitempty"`
+	TableNumber int                `json:"table_number,omitempty" bson:"table_number,omitempty"`
+	OrderId     primitive.ObjectID `json:"order_id,omitempty" bson:"order_id,omitempty"`
+	LeftAt      time.Time          `json:"left_at,omitempty" bson:"left_at,omitempty"`
+	ClosedBy    primitive.ObjectID `json:"closed_by,omitempty" bson:"closed_by,omitempty"`
+}
+
+// Wait is how long the party waited, or has waited so far.
+func (entry *WaitlistEntry) Wait(now time.Time) time.Duration {
+	switch entry.Status {
+	case WaitlistSeated:
+		return entry.SeatedAt.Sub(entry.JoinedAt)
+	case WaitlistWalkedAway:
+		return entry.LeftAt.Sub(entry.JoinedAt)
+	}
+	return now.Sub(entry.JoinedAt)
+}
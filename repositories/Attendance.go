@@ -122,4 +122,19 @@ func (repo *MongoRepository) TodaysWorkingTime(id string) (float64, error) {
         totalWorkDuration += time.Since(lastCheckIn).Minutes()
     }
 	return totalWorkDuration, nil
-}
\ No newline at end of file
+}
+// GetAttendanceBetween returns every employee's check-ins and check-outs in
+// [from, to), in time order.
+func (repo *MongoRepository) GetAttendanceBetween(from, to time.Time) ([]models.Attendance, error) {
+	attendances := []models.Attendance{}
+	cursor, err := repo.AttendanceCollection.Find(context.TODO(),
+		bson.M{"time": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &attendances); err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
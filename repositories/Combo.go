@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateCombo(combo *models.Combo) error {
+	combo.Id = primitive.NewObjectID()
+	_, err := repo.ComboCollection.InsertOne(context.Background(), combo)
+	return err
+}
+
+func (repo *MongoRepository) UpdateCombo(combo *models.Combo) error {
+	res, err := repo.ComboCollection.ReplaceOne(context.Background(), bson.M{"_id": combo.Id}, combo)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("combo not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) DeleteCombo(id string) error {
+	cid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := repo.ComboCollection.DeleteOne(context.Background(), bson.M{"_id": cid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("combo not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) GetComboById(id string) (*models.Combo, error) {
+	var combo models.Combo
+	cid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.ComboCollection.FindOne(context.Background(), bson.M{"_id": cid}).Decode(&combo)
+	if err != nil {
+		return nil, err
+	}
+	return &combo, nil
+}
+
+func (repo *MongoRepository) GetAllCombos() ([]models.Combo, error) {
+	combos := []models.Combo{}
+	cursor, err := repo.ComboCollection.Find(context.Background(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &combos); err != nil {
+		return nil, err
+	}
+	return combos, nil
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NextSequence atomically increments and returns the named counter.
+func (repo *MongoRepository) NextSequence(name string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := repo.CounterCollection.FindOneAndUpdate(context.Background(),
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		opts).Decode(&counter)
+	return counter.Seq, err
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// ErrPhoneTaken is returned when a phone number already belongs to another customer.
+var ErrPhoneTaken = errors.New("phone number already belongs to a customer")
+
+func (repo *MongoRepository) CreateCustomer(customer *models.Customer) error {
+	customer.Id = primitive.NewObjectID()
+	_, err := repo.CustomerCollection.InsertOne(context.Background(), customer)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrPhoneTaken
+	}
+	return err
+}
+
+// UpdateCustomerProfile changes the name, phone and birthday of a customer;
+// visits, spend and points only move with payments.
+func (repo *MongoRepository) UpdateCustomerProfile(customer *models.Customer) error {
+	res, err := repo.CustomerCollection.UpdateOne(context.Background(), bson.M{"_id": customer.Id}, bson.M{"$set": bson.M{
+		"phone":    customer.Phone,
+		"name":     customer.Name,
+		"birthday": customer.Birthday,
+	}})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrPhoneTaken
+	}
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("customer not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) GetCustomerById(id string) (*models.Customer, error) {
+	var customer models.Customer
+	cid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.CustomerCollection.FindOne(context.Background(), bson.M{"_id": cid}).Decode(&customer)
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (repo *MongoRepository) GetCustomerByPhone(phone string) (*models.Customer, error) {
+	var customer models.Customer
+	err := repo.CustomerCollection.FindOne(context.Background(), bson.M{"phone": phone}).Decode(&customer)
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// RecordCustomerSpend adds spend and points to a customer, counting a visit
+// if visit is set. Negative amounts take them back; the points balance never
+// drops below zero.
+func (repo *MongoRepository) RecordCustomerSpend(id primitive.ObjectID, spend float64, points int, visit bool, at time.Time) error {
+	set := bson.M{
+		"lifetime_spend": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$lifetime_spend", 0}}, spend}},
+		"points":         bson.M{"$max": bson.A{0, bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$points", 0}}, points}}}},
+	}
+	if visit {
+		set["visits"] = bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$visits", 0}}, 1}}
+		set["first_visit_at"] = bson.M{"$ifNull": bson.A{"$first_visit_at", at}}
+		set["last_visit_at"] = at
+	}
+	res, err := repo.CustomerCollection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.A{bson.M{"$set": set}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("customer not found")
+	}
+	return nil
+}
+
+// RedeemCustomerPoints takes points off a customer's balance, provided the
+// balance covers them.
+func (repo *MongoRepository) RedeemCustomerPoints(id primitive.ObjectID, points int) error {
+	res, err := repo.CustomerCollection.UpdateOne(context.Background(),
+		bson.M{"_id": id, "points": bson.M{"$gte": points}},
+		bson.M{"$inc": bson.M{"points": -points}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("customer not found or not enough points")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) CreateLoyaltyEntry(entry *models.LoyaltyEntry) error {
+	entry.Id = primitive.NewObjectID()
+	_, err := repo.LoyaltyCollection.InsertOne(context.Background(), entry)
+	return err
+}
+
+// GetLoyaltyEntries returns a customer's points movements, newest first.
+func (repo *MongoRepository) GetLoyaltyEntries(customerId primitive.ObjectID) ([]models.LoyaltyEntry, error) {
+	entries := []models.LoyaltyEntry{}
+	cursor, err := repo.LoyaltyCollection.Find(context.Background(),
+		bson.M{"customer_id": customerId},
+		options.Find().SetSort(bson.D{{Key: "at", Value: -1}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -71,3 +71,19 @@ func (repo *MongoRepository) GetAllDrinks() ([]models.Drink, error) {
 	return drinks, nil
 
 }
+
+// SetDrinkSoldOut marks a drink sold out or back on.
+func (repo *MongoRepository) SetDrinkSoldOut(id string, soldOut bool) error {
+	did, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := repo.DrinkCollection.UpdateOne(context.Background(), bson.M{"_id": did}, bson.M{"$set": bson.M{"sold_out": soldOut}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("drink not found")
+	}
+	return nil
+}
@@ -70,3 +70,19 @@ func (repo *MongoRepository) GetAllFoods() ([]models.Food, error) {
 	return foods, nil
 
 }
+
+// SetFoodSoldOut marks a food sold out or back on.
+func (repo *MongoRepository) SetFoodSoldOut(id string, soldOut bool) error {
+	fid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := repo.FoodCollection.UpdateOne(context.Background(), bson.M{"_id": fid}, bson.M{"$set": bson.M{"sold_out": soldOut}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("food not found")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// ReserveIdempotencyKey stores record as in progress. If the key was already
+// used, nothing is written and the stored record is returned instead.
+func (repo *MongoRepository) ReserveIdempotencyKey(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	_, err := repo.IdempotencyCollection.InsertOne(context.Background(), record)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+	var existing models.IdempotencyRecord
+	if err := repo.IdempotencyCollection.FindOne(context.Background(), bson.M{"_id": record.Id}).Decode(&existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request made with the key.
+func (repo *MongoRepository) CompleteIdempotencyKey(id string, statusCode int, contentType string, body []byte) error {
+	res, err := repo.IdempotencyCollection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"state":        models.IdempotencyDone,
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"body":         body,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("idempotency key not found")
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets a key whose request failed so it can be retried.
+func (repo *MongoRepository) ReleaseIdempotencyKey(id string) error {
+	_, err := repo.IdempotencyCollection.DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateMenuSchedule(schedule *models.MenuSchedule) error {
+	schedule.Id = primitive.NewObjectID()
+	_, err := repo.MenuScheduleCollection.InsertOne(context.Background(), schedule)
+	return err
+}
+
+func (repo *MongoRepository) UpdateMenuSchedule(schedule *models.MenuSchedule) error {
+	res, err := repo.MenuScheduleCollection.ReplaceOne(context.Background(), bson.M{"_id": schedule.Id}, schedule)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("menu schedule not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) DeleteMenuSchedule(id string) error {
+	sid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := repo.MenuScheduleCollection.DeleteOne(context.Background(), bson.M{"_id": sid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("menu schedule not found")
+	}
+	return nil
+}
+
+// GetMenuSchedules returns the menu schedules, only the active ones if asked,
+// oldest first.
+func (repo *MongoRepository) GetMenuSchedules(activeOnly bool) ([]models.MenuSchedule, error) {
+	schedules := []models.MenuSchedule{}
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+	cursor, err := repo.MenuScheduleCollection.Find(context.Background(), filter,
+		options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
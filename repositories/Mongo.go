@@ -7,6 +7,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
 )
 
 type MongoRepository struct {
@@ -15,6 +17,26 @@ type MongoRepository struct {
 	FoodCollection       *mongo.Collection
 	DrinkCollection      *mongo.Collection
 	AttendanceCollection *mongo.Collection
+	OrderEventCollection *mongo.Collection
+	CounterCollection    *mongo.Collection
+	TableCollection      *mongo.Collection
+	PaymentCollection    *mongo.Collection
+	ReceiptCollection    *mongo.Collection
+	SettingsCollection   *mongo.Collection
+
+	ReconciliationCollection *mongo.Collection
+	OrderHistoryCollection   *mongo.Collection
+	IdempotencyCollection    *mongo.Collection
+	ReservationCollection    *mongo.Collection
+	WaitlistCollection       *mongo.Collection
+	CustomerCollection       *mongo.Collection
+	LoyaltyCollection        *mongo.Collection
+	VoucherCollection        *mongo.Collection
+	VoucherLedgerCollection  *mongo.Collection
+	TipCollection            *mongo.Collection
+	PricingRuleCollection    *mongo.Collection
+	ComboCollection          *mongo.Collection
+	MenuScheduleCollection   *mongo.Collection
 }
 
 func NewRepo() RepositoryInterface {
@@ -30,6 +52,25 @@ func NewRepo() RepositoryInterface {
 	FoodCollection := db.Collection("Food")
 	DrinkCollection := db.Collection("Drink")
 	AttendanceCollection := db.Collection("Attendance")
+	OrderEventCollection := db.Collection("OrderEvent")
+	CounterCollection := db.Collection("Counter")
+	TableCollection := db.Collection("Table")
+	PaymentCollection := db.Collection("Payment")
+	ReceiptCollection := db.Collection("Receipt")
+	SettingsCollection := db.Collection("Settings")
+	OrderHistoryCollection := db.Collection("OrderHistory")
+	IdempotencyCollection := db.Collection("IdempotencyKey")
+	ReservationCollection := db.Collection("Reservation")
+	WaitlistCollection := db.Collection("Waitlist")
+	CustomerCollection := db.Collection("Customer")
+	LoyaltyCollection := db.Collection("LoyaltyLedger")
+	VoucherCollection := db.Collection("Voucher")
+	VoucherLedgerCollection := db.Collection("VoucherLedger")
+	TipCollection := db.Collection("Tip")
+	PricingRuleCollection := db.Collection("PricingRule")
+	ComboCollection := db.Collection("Combo")
+	MenuScheduleCollection := db.Collection("MenuSchedule")
+	ReconciliationCollection := db.Collection("CashReconciliation")
 
 	EmployeeIndexModel := mongo.IndexModel{
 		Keys: bson.D{
@@ -65,16 +106,154 @@ func NewRepo() RepositoryInterface {
 		panic(err)
 	}
 
-	// OrderIndexModel := mongo.IndexModel{
-	// 	Keys: bson.M{
-	// 		"name": 1, // Field to index (1 for ascending order)
-	// 	},
-	// 	Options: options.Index().SetUnique(true),
-	// }
-	// _, err = OrderCollection.Indexes().CreateOne(context.TODO(), OrderIndexModel)
-	// if err != nil {
-	// 	panic(err)
-	// }
+	OrderEventIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"seq": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err = OrderEventCollection.Indexes().CreateOne(context.TODO(), OrderEventIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	OrderEventExpiryIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"at": 1},
+		Options: options.Index().SetExpireAfterSeconds(int32(models.OrderEventRetention.Seconds())),
+	}
+	_, err = OrderEventCollection.Indexes().CreateOne(context.TODO(), OrderEventExpiryIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	TableIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"number": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err = TableCollection.Indexes().CreateOne(context.TODO(), TableIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	PaymentIndexModel := mongo.IndexModel{
+		Keys: bson.M{"order_id": 1},
+	}
+	_, err = PaymentCollection.Indexes().CreateOne(context.TODO(), PaymentIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	ReceiptIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"order_id": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err = ReceiptCollection.Indexes().CreateOne(context.TODO(), ReceiptIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	OrderHistoryIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "order_id", Value: 1}, {Key: "at", Value: 1}},
+	}
+	_, err = OrderHistoryCollection.Indexes().CreateOne(context.TODO(), OrderHistoryIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	IdempotencyIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"created_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(int32(models.IdempotencyRetention.Seconds())),
+	}
+	_, err = IdempotencyCollection.Indexes().CreateOne(context.TODO(), IdempotencyIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	ReservationIndexModels := []mongo.IndexModel{
+		{Keys: bson.M{"starts_at": 1}},
+		{Keys: bson.D{{Key: "table_number", Value: 1}, {Key: "starts_at", Value: 1}}},
+	}
+	_, err = ReservationCollection.Indexes().CreateMany(context.TODO(), ReservationIndexModels)
+	if err != nil {
+		panic(err)
+	}
+
+	WaitlistIndexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "joined_at", Value: 1}}},
+		{Keys: bson.M{"joined_at": 1}},
+	}
+	_, err = WaitlistCollection.Indexes().CreateMany(context.TODO(), WaitlistIndexModels)
+	if err != nil {
+		panic(err)
+	}
+
+	CustomerIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"phone": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err = CustomerCollection.Indexes().CreateOne(context.TODO(), CustomerIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	LoyaltyIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "at", Value: -1}},
+	}
+	_, err = LoyaltyCollection.Indexes().CreateOne(context.TODO(), LoyaltyIndexModel)
+	if err != nil {
+		panic(err)
+	}
+
+	VoucherIndexModel := mongo.IndexModel{
+		Keys:    bson.M{"code": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err = VoucherCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		VoucherIndexModel,
+		{Keys: bson.D{{Key: "expires_at", Value: 1}, {Key: "balance", Value: 1}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	VoucherLedgerIndexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "voucher_id", Value: 1}, {Key: "at", Value: 1}}},
+		{Keys: bson.M{"at": 1}},
+	}
+	_, err = VoucherLedgerCollection.Indexes().CreateMany(context.TODO(), VoucherLedgerIndexModels)
+	if err != nil {
+		panic(err)
+	}
+
+	TipIndexModels := []mongo.IndexModel{
+		{Keys: bson.M{"at": 1}},
+		{Keys: bson.M{"order_id": 1}},
+	}
+	_, err = TipCollection.Indexes().CreateMany(context.TODO(), TipIndexModels)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = PricingRuleCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{Keys: bson.M{"active": 1}})
+	if err != nil {
+		panic(err)
+	}
+
+	// OrderIndexModels back the order listing filters and sorts
+	OrderIndexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "total_price", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "table_number", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "employee_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.M{"foods.food_id ": 1}},
+		{Keys: bson.M{"drinks.drink_id": 1}},
+		{Keys: bson.M{"guest_session": 1}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	_, err = OrderCollection.Indexes().CreateMany(context.TODO(), OrderIndexModels)
+	if err != nil {
+		panic(err)
+	}
 
 	return &MongoRepository{
 		EmployeeCollection:   EmployeeCollection,
@@ -82,6 +261,26 @@ func NewRepo() RepositoryInterface {
 		FoodCollection:       FoodCollection,
 		DrinkCollection:      DrinkCollection,
 		AttendanceCollection: AttendanceCollection,
+		OrderEventCollection: OrderEventCollection,
+		CounterCollection:    CounterCollection,
+		TableCollection:      TableCollection,
+		PaymentCollection:    PaymentCollection,
+		ReceiptCollection:    ReceiptCollection,
+		SettingsCollection:   SettingsCollection,
+
+		ReconciliationCollection: ReconciliationCollection,
+		OrderHistoryCollection:   OrderHistoryCollection,
+		IdempotencyCollection:    IdempotencyCollection,
+		ReservationCollection:    ReservationCollection,
+		WaitlistCollection:       WaitlistCollection,
+		CustomerCollection:       CustomerCollection,
+		LoyaltyCollection:        LoyaltyCollection,
+		VoucherCollection:        VoucherCollection,
+		VoucherLedgerCollection:  VoucherLedgerCollection,
+		TipCollection:            TipCollection,
+		PricingRuleCollection:    PricingRuleCollection,
+		ComboCollection:          ComboCollection,
+		MenuScheduleCollection:   MenuScheduleCollection,
 	}
 
 }
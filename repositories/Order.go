@@ -2,46 +2,124 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/yesetoda/kushena/models"
 )
 
-func (repo *MongoRepository) CreateOrder(order models.Order) error {
+func (repo *MongoRepository) CreateOrder(order *models.Order) error {
 	order.Id = primitive.NewObjectID()
 	_, err := repo.OrderCollection.InsertOne(context.Background(), order)
 	return err
 }
 
+// ErrVersionConflict is returned when an order changed since it was read.
+var ErrVersionConflict = errors.New("order was changed by someone else")
+
+// versionFilter matches an order at the given version. Orders saved before
+// versioning have no version field and count as version 0.
+func versionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": id, "version": version}
+}
+
+// orderWriteMiss tells a missing order apart from a version conflict after an
+// update matched nothing.
+func (repo *MongoRepository) orderWriteMiss(id primitive.ObjectID) error {
+	count, err := repo.OrderCollection.CountDocuments(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("order not found")
+	}
+	return ErrVersionConflict
+}
+
+// UpdateOrder replaces the stored order's fields with order, provided the
+// stored version is still order.Version. On success order.Version is bumped.
 func (repo *MongoRepository) UpdateOrder(order *models.Order) error {
-	res, err := repo.OrderCollection.UpdateOne(context.Background(), bson.M{"_id": order.Id}, bson.M{"$set": order})
+	expected := order.Version
+	order.Version++
+	res, err := repo.OrderCollection.UpdateOne(context.Background(), versionFilter(order.Id, expected), bson.M{"$set": order})
 	if err != nil {
+		order.Version = expected
 		return err
 	}
 	if res.MatchedCount == 0 {
-		return fmt.Errorf("order not found")
+		order.Version = expected
+		return repo.orderWriteMiss(order.Id)
 	}
-	return err
+	return nil
+}
 
+// PatchOrder sets only the given fields on an order at the given version and
+// bumps its version. With no fields it only bumps the version, claiming the
+// order against concurrent writers.
+func (repo *MongoRepository) PatchOrder(id primitive.ObjectID, version int64, set, unset bson.M) error {
+	update := bson.M{"$inc": bson.M{"version": 1}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	res, err := repo.OrderCollection.UpdateOne(context.Background(), versionFilter(id, version), update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return repo.orderWriteMiss(id)
+	}
+	return nil
 }
-func (repo *MongoRepository) DeleteOrder(id string) error {
+
+// UpdateOrderStatus moves an order to change.To, provided it is still in
+// change.From, and appends the change to the order's status history.
+func (repo *MongoRepository) UpdateOrderStatus(id string, change models.StatusChange) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
-	res, err := repo.OrderCollection.DeleteOne(context.Background(), bson.M{"_id": oid})
+	res, err := repo.OrderCollection.UpdateOne(context.Background(),
+		bson.M{"_id": oid, "status": change.From},
+		bson.M{
+			"$set":  bson.M{"status": change.To},
+			"$push": bson.M{"status_history": change},
+			"$inc":  bson.M{"version": 1},
+		})
 	if err != nil {
 		return err
 	}
-	if res.DeletedCount == 0 {
-		return fmt.Errorf("order not found")
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("order not found or status changed")
 	}
-	return err
+	return nil
+}
 
+// GetPendingVoids returns the orders whose void waits for a manager, oldest request first.
+func (repo *MongoRepository) GetPendingVoids() ([]models.Order, error) {
+	var orders []models.Order
+	cursor, err := repo.OrderCollection.Find(context.Background(),
+		bson.M{"void.approved": false},
+		options.Find().SetSort(bson.M{"void.requested_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
 }
+
 func (repo *MongoRepository) GetOrderById(id string) (*models.Order, error) {
 	var order models.Order
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -52,43 +130,57 @@ func (repo *MongoRepository) GetOrderById(id string) (*models.Order, error) {
 	return &order, err
 
 }
-func (repo *MongoRepository) GetAllOrders() ([]models.Order, error) {
+// GetOpenOrdersByTable returns the orders at a table that are not yet closed, oldest first.
+func (repo *MongoRepository) GetOpenOrdersByTable(number int) ([]models.Order, error) {
 	var orders []models.Order
-	cursor, err := repo.OrderCollection.Find(context.Background(), bson.M{})
+	cursor, err := repo.OrderCollection.Find(context.Background(),
+		bson.M{"table_number": number, "status": bson.M{"$nin": models.ClosedOrderStatuses}},
+		options.Find().SetSort(bson.M{"created_at": 1}))
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
-		var order models.Order
-		err := cursor.Decode(&order)
-		if err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
+	if err := cursor.All(context.Background(), &orders); err != nil {
+		return nil, err
 	}
 	return orders, nil
 }
 
-func (repo *MongoRepository) GetAllMyOrders(id string) ([]models.Order, error) {
-	eid, err := primitive.ObjectIDFromHex(id)
+// SettleBillPart marks one unsettled part of a split check as settled.
+func (repo *MongoRepository) SettleBillPart(id string, number int, employeeId primitive.ObjectID, at time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
-    }
+		return err
+	}
+	res, err := repo.OrderCollection.UpdateOne(context.Background(),
+		bson.M{"_id": oid, "splits": bson.M{"$elemMatch": bson.M{"number": number, "settled": false}}},
+		bson.M{
+			"$set": bson.M{
+				"splits.$.settled":    true,
+				"splits.$.settled_by": employeeId,
+				"splits.$.settled_at": at,
+			},
+			"$inc": bson.M{"version": 1},
+		})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("bill part not found or already settled")
+	}
+	return nil
+}
+
+// GetOrdersByGuestSession returns the orders placed with one guest token, oldest first.
+func (repo *MongoRepository) GetOrdersByGuestSession(session string) ([]models.Order, error) {
 	var orders []models.Order
-	cursor, err := repo.OrderCollection.Find(context.Background(), bson.M{"employee_id":eid})
+	cursor, err := repo.OrderCollection.Find(context.Background(),
+		bson.M{"guest_session": session},
+		options.Find().SetSort(bson.M{"created_at": 1}))
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
-		var order models.Order
-		err := cursor.Decode(&order)
-		if err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
+	if err := cursor.All(context.Background(), &orders); err != nil {
+		return nil, err
 	}
-	fmt.Println("orders", orders)
 	return orders, nil
 }
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateOrderEvent(event *models.OrderEvent) error {
+	seq, err := repo.NextSequence("order_event")
+	if err != nil {
+		return err
+	}
+	event.Id = primitive.NewObjectID()
+	event.Seq = seq
+	_, err = repo.OrderEventCollection.InsertOne(context.Background(), event)
+	return err
+}
+
+// GetOrderEventsAfter returns every event with a sequence greater than seq, oldest first.
+func (repo *MongoRepository) GetOrderEventsAfter(seq int64) ([]models.OrderEvent, error) {
+	cursor, err := repo.OrderEventCollection.Find(context.Background(),
+		bson.M{"seq": bson.M{"$gt": seq}},
+		options.Find().SetSort(bson.M{"seq": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var events []models.OrderEvent
+	if err := cursor.All(context.Background(), &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateOrderChange(change *models.OrderChange) error {
+	change.Id = primitive.NewObjectID()
+	_, err := repo.OrderHistoryCollection.InsertOne(context.Background(), change)
+	return err
+}
+
+func (repo *MongoRepository) GetOrderHistory(orderId primitive.ObjectID) ([]models.OrderChange, error) {
+	var changes []models.OrderChange
+	cursor, err := repo.OrderHistoryCollection.Find(context.Background(), bson.M{"order_id": orderId},
+		options.Find().SetSort(bson.M{"at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// ErrInvalidCursor is returned for a page token that was not issued for the
+// requested sort.
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// pageCursor is the position after the last order of a page: its sort value
+// and id, so ties on the sort value are broken by id.
+type pageCursor struct {
+	Sort  string             `json:"s"`
+	Time  time.Time          `json:"t,omitempty"`
+	Total float64            `json:"v,omitempty"`
+	Id    primitive.ObjectID `json:"id"`
+}
+
+func encodeCursor(sort string, order *models.Order) string {
+	cursor := pageCursor{Sort: sort, Id: order.Id}
+	if strings.TrimPrefix(sort, "-") == "total_price" {
+		cursor.Total = order.TotalPrice
+	} else {
+		cursor.Time = order.CreatedAt
+	}
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(sort, token string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// orderFilter turns the query's filters into a Mongo filter.
+func orderFilter(query models.OrderQuery) bson.A {
+	and := bson.A{}
+	created := bson.M{}
+	if !query.From.IsZero() {
+		created["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		created["$lt"] = query.To
+	}
+	if len(created) > 0 {
+		and = append(and, bson.M{"created_at": created})
+	}
+	if len(query.Statuses) > 0 {
+		and = append(and, bson.M{"status": bson.M{"$in": query.Statuses}})
+	}
+	if query.Type == models.OrderDineIn {
+		// orders taken before types existed have none and are dine-in
+		and = append(and, bson.M{"type": bson.M{"$in": bson.A{models.OrderDineIn, "", nil}}})
+	} else if query.Type != "" {
+		and = append(and, bson.M{"type": query.Type})
+	}
+	if query.TableNumber != 0 {
+		and = append(and, bson.M{"table_number": query.TableNumber})
+	}
+	if !query.EmployeeId.IsZero() {
+		and = append(and, bson.M{"employee_id": query.EmployeeId})
+	}
+	total := bson.M{}
+	if query.MinTotal != nil {
+		total["$gte"] = *query.MinTotal
+	}
+	if query.MaxTotal != nil {
+		total["$lte"] = *query.MaxTotal
+	}
+	if len(total) > 0 {
+		and = append(and, bson.M{"total_price": total})
+	}
+	if !query.ItemId.IsZero() {
+		// the food line id is stored under "food_id " with the trailing space
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"foods.food_id ": query.ItemId},
+			bson.M{"drinks.drink_id": query.ItemId},
+		}})
+	}
+	return and
+}
+
+// FindOrders returns one page of the orders matching query, paged by keyset on
+// the sort field and id so pages stay stable while new orders come in.
+func (repo *MongoRepository) FindOrders(query models.OrderQuery) (*models.OrderPage, error) {
+	field := strings.TrimPrefix(query.Sort, "-")
+	direction, compare := 1, "$gt"
+	if strings.HasPrefix(query.Sort, "-") {
+		direction, compare = -1, "$lt"
+	}
+
+	and := orderFilter(query)
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Sort, query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{} = cursor.Time
+		if field == "total_price" {
+			value = cursor.Total
+		}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{field: bson.M{compare: value}},
+			bson.M{field: value, "_id": bson.M{compare: cursor.Id}},
+		}})
+	}
+	filter := bson.M{}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(query.Limit + 1))
+	cursor, err := repo.OrderCollection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	page := models.OrderPage{Orders: []models.Order{}}
+	if err := cursor.All(context.Background(), &page.Orders); err != nil {
+		return nil, err
+	}
+	if len(page.Orders) > query.Limit {
+		page.Orders = page.Orders[:query.Limit]
+		page.NextCursor = encodeCursor(query.Sort, &page.Orders[query.Limit-1])
+	}
+	return &page, nil
+}
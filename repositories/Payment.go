@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreatePayment(payment *models.Payment) error {
+	payment.Id = primitive.NewObjectID()
+	_, err := repo.PaymentCollection.InsertOne(context.Background(), payment)
+	return err
+}
+
+func (repo *MongoRepository) GetPaymentById(id string) (*models.Payment, error) {
+	var payment models.Payment
+	pid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.PaymentCollection.FindOne(context.Background(), bson.M{"_id": pid}).Decode(&payment)
+	return &payment, err
+}
+
+func (repo *MongoRepository) GetPaymentsByOrder(orderId primitive.ObjectID) ([]models.Payment, error) {
+	var payments []models.Payment
+	cursor, err := repo.PaymentCollection.Find(context.Background(), bson.M{"order_id": orderId},
+		options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// AddRefund appends a refund to a payment. The update only applies if the
+// refunded total is still what the caller read, so two refunds can't both pass
+// the same balance check.
+func (repo *MongoRepository) AddRefund(id primitive.ObjectID, refunded float64, refund models.Refund) error {
+	res, err := repo.PaymentCollection.UpdateOne(context.Background(),
+		bson.M{"_id": id, "refunded": refunded},
+		bson.M{
+			"$inc":  bson.M{"refunded": refund.Amount},
+			"$push": bson.M{"refunds": refund},
+		})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("payment not found or refunded concurrently")
+	}
+	return nil
+}
+
+// GetEmployeePayments returns the payments taken by an employee, or refunded by
+// them, between from and to.
+func (repo *MongoRepository) GetEmployeePayments(employeeId primitive.ObjectID, from, to time.Time) ([]models.Payment, error) {
+	var payments []models.Payment
+	window := bson.M{"$gte": from, "$lt": to}
+	cursor, err := repo.PaymentCollection.Find(context.Background(), bson.M{"$or": bson.A{
+		bson.M{"employee_id": employeeId, "created_at": window},
+		bson.M{"refunds": bson.M{"$elemMatch": bson.M{"employee_id": employeeId, "at": window}}},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (repo *MongoRepository) CreateReconciliation(reconciliation *models.CashReconciliation) error {
+	reconciliation.Id = primitive.NewObjectID()
+	_, err := repo.ReconciliationCollection.InsertOne(context.Background(), reconciliation)
+	return err
+}
+
+func (repo *MongoRepository) GetReconciliations(from, to time.Time) ([]models.CashReconciliation, error) {
+	var reconciliations []models.CashReconciliation
+	cursor, err := repo.ReconciliationCollection.Find(context.Background(),
+		bson.M{"created_at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &reconciliations); err != nil {
+		return nil, err
+	}
+	return reconciliations, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreatePricingRule(rule *models.PricingRule) error {
+	rule.Id = primitive.NewObjectID()
+	_, err := repo.PricingRuleCollection.InsertOne(context.Background(), rule)
+	return err
+}
+
+func (repo *MongoRepository) UpdatePricingRule(rule *models.PricingRule) error {
+	res, err := repo.PricingRuleCollection.ReplaceOne(context.Background(), bson.M{"_id": rule.Id}, rule)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("pricing rule not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) DeletePricingRule(id string) error {
+	rid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := repo.PricingRuleCollection.DeleteOne(context.Background(), bson.M{"_id": rid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("pricing rule not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) GetPricingRuleById(id string) (*models.PricingRule, error) {
+	var rule models.PricingRule
+	rid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.PricingRuleCollection.FindOne(context.Background(), bson.M{"_id": rid}).Decode(&rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetPricingRules returns the pricing rules, only the active ones if asked,
+// oldest first.
+func (repo *MongoRepository) GetPricingRules(activeOnly bool) ([]models.PricingRule, error) {
+	rules := []models.PricingRule{}
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+	cursor, err := repo.PricingRuleCollection.Find(context.Background(), filter,
+		options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// IssueReceipt returns the receipt of an order, numbering a new one if the
+// order has none yet. The next number is the highest issued plus one and is
+// only taken by a successful insert, so the sequence has no gaps; a clash on
+// either the number or the order just means another request won and we retry.
+func (repo *MongoRepository) IssueReceipt(orderId, employeeId primitive.ObjectID) (*models.Receipt, error) {
+	for {
+		var receipt models.Receipt
+		err := repo.ReceiptCollection.FindOne(context.Background(), bson.M{"order_id": orderId}).Decode(&receipt)
+		if err == nil {
+			return &receipt, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+
+		var last models.Receipt
+		err = repo.ReceiptCollection.FindOne(context.Background(), bson.M{},
+			options.FindOne().SetSort(bson.M{"_id": -1})).Decode(&last)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+
+		receipt = models.Receipt{
+			Number:     last.Number + 1,
+			OrderId:    orderId,
+			EmployeeId: employeeId,
+			IssuedAt:   time.Now().UTC(),
+		}
+		_, err = repo.ReceiptCollection.InsertOne(context.Background(), receipt)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &receipt, nil
+	}
+}
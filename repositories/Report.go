@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/yesetoda/kushena/models"
@@ -62,13 +63,19 @@ func (repo *MongoRepository) Report(interval string) ([]byte, error) {
 			orderReport := generateOrderReport(repo.OrderCollection, beforeDay, endDate, "Daily")
 			employeePerformanceReport := generateEmployeePerformanceReport(repo.AttendanceCollection, repo.OrderCollection, beforeDay, endDate, "Daily")
 			operationalEfficiencyReport := generateOperationalEfficiencyReport(repo.AttendanceCollection, repo.OrderCollection, beforeDay, endDate, "Daily")
-			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, beforeDay, endDate, "Daily")
+			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, repo.PaymentCollection, beforeDay, endDate, "Daily")
+			waitlistReport := generateWaitlistReport(repo.WaitlistCollection, beforeDay, endDate, "Daily")
+			customerReport := generateCustomerReport(repo.OrderCollection, repo.CustomerCollection, beforeDay, endDate, "Daily")
+			itemSalesReport := generateItemSalesReport(repo.OrderCollection, beforeDay, endDate, "Daily")
 
 			combined := map[string]interface{}{
 				"order_report":                  orderReport,
 				"employee_performance_report":   employeePerformanceReport,
 				"operational_efficiency_report": operationalEfficiencyReport,
 				"revenue_financial_report":      revenueFinancialReport,
+				"waitlist_report":               waitlistReport,
+				"customer_report":               customerReport,
+				"item_sales_report":             itemSalesReport,
 			}
 			b, err := json.MarshalIndent(combined, "", "  ")
 			if err != nil {
@@ -91,13 +98,19 @@ func (repo *MongoRepository) Report(interval string) ([]byte, error) {
 			orderReport := generateOrderReport(repo.OrderCollection, beforeWeek, endDate, "Weekly")
 			employeePerformanceReport := generateEmployeePerformanceReport(repo.AttendanceCollection, repo.OrderCollection, beforeWeek, endDate, "Weekly")
 			operationalEfficiencyReport := generateOperationalEfficiencyReport(repo.AttendanceCollection, repo.OrderCollection, beforeWeek, endDate, "Weekly")
-			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, beforeWeek, endDate, "Weekly")
+			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, repo.PaymentCollection, beforeWeek, endDate, "Weekly")
+			waitlistReport := generateWaitlistReport(repo.WaitlistCollection, beforeWeek, endDate, "Weekly")
+			customerReport := generateCustomerReport(repo.OrderCollection, repo.CustomerCollection, beforeWeek, endDate, "Weekly")
+			itemSalesReport := generateItemSalesReport(repo.OrderCollection, beforeWeek, endDate, "Weekly")
 
 			combined := map[string]interface{}{
 				"order_report":                  orderReport,
 				"employee_performance_report":   employeePerformanceReport,
 				"operational_efficiency_report": operationalEfficiencyReport,
 				"revenue_financial_report":      revenueFinancialReport,
+				"waitlist_report":               waitlistReport,
+				"customer_report":               customerReport,
+				"item_sales_report":             itemSalesReport,
 			}
 			b, err := json.MarshalIndent(combined, "", "  ")
 			if err != nil {
@@ -120,13 +133,21 @@ func (repo *MongoRepository) Report(interval string) ([]byte, error) {
 			orderReport := generateOrderReport(repo.OrderCollection, beforeMonth, endDate, "Monthly")
 			employeePerformanceReport := generateEmployeePerformanceReport(repo.AttendanceCollection, repo.OrderCollection, beforeMonth, endDate, "Monthly")
 			operationalEfficiencyReport := generateOperationalEfficiencyReport(repo.AttendanceCollection, repo.OrderCollection, beforeMonth, endDate, "Monthly")
-			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, beforeMonth, endDate, "Monthly")
+			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, repo.PaymentCollection, beforeMonth, endDate, "Monthly")
+			waitlistReport := generateWaitlistReport(repo.WaitlistCollection, beforeMonth, endDate, "Monthly")
+			customerReport := generateCustomerReport(repo.OrderCollection, repo.CustomerCollection, beforeMonth, endDate, "Monthly")
+			itemSalesReport := generateItemSalesReport(repo.OrderCollection, beforeMonth, endDate, "Monthly")
+			voucherReport := generateVoucherReport(repo.VoucherCollection, repo.VoucherLedgerCollection, beforeMonth, endDate, "Monthly")
 
 			combined := map[string]interface{}{
 				"order_report":                  orderReport,
 				"employee_performance_report":   employeePerformanceReport,
 				"operational_efficiency_report": operationalEfficiencyReport,
 				"revenue_financial_report":      revenueFinancialReport,
+				"waitlist_report":               waitlistReport,
+				"customer_report":               customerReport,
+				"item_sales_report":             itemSalesReport,
+				"voucher_report":                voucherReport,
 			}
 			b, err := json.MarshalIndent(combined, "", "  ")
 			if err != nil {
@@ -149,13 +170,19 @@ func (repo *MongoRepository) Report(interval string) ([]byte, error) {
 			orderReport := generateOrderReport(repo.OrderCollection, beforeYear, endDate, "Yearly")
 			employeePerformanceReport := generateEmployeePerformanceReport(repo.AttendanceCollection, repo.OrderCollection, beforeYear, endDate, "Yearly")
 			operationalEfficiencyReport := generateOperationalEfficiencyReport(repo.AttendanceCollection, repo.OrderCollection, beforeYear, endDate, "Yearly")
-			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, beforeYear, endDate, "Yearly")
+			revenueFinancialReport := generateRevenueFinancialReport(repo.OrderCollection, repo.PaymentCollection, beforeYear, endDate, "Yearly")
+			waitlistReport := generateWaitlistReport(repo.WaitlistCollection, beforeYear, endDate, "Yearly")
+			customerReport := generateCustomerReport(repo.OrderCollection, repo.CustomerCollection, beforeYear, endDate, "Yearly")
+			itemSalesReport := generateItemSalesReport(repo.OrderCollection, beforeYear, endDate, "Yearly")
 
 			combined := map[string]interface{}{
 				"order_report":                  orderReport,
 				"employee_performance_report":   employeePerformanceReport,
 				"operational_efficiency_report": operationalEfficiencyReport,
 				"revenue_financial_report":      revenueFinancialReport,
+				"waitlist_report":               waitlistReport,
+				"customer_report":               customerReport,
+				"item_sales_report":             itemSalesReport,
 			}
 			b, err := json.MarshalIndent(combined, "", "  ")
 			if err != nil {
@@ -194,15 +221,17 @@ func generateOrderReport(collection *mongo.Collection, startDate, endDate time.T
 		log.Fatalf("Error decoding orders for %s report: %v", period, err)
 	}
 
+	kept, voided := splitVoided(orders)
+	voids := voidSummary(voided)
 	totalRevenue := 0.0
-	totalOrders := len(orders)
+	totalOrders := len(kept)
 	peakHours := make(map[string]int)
 	itemSales := make(map[string]int)
 	var orderValues []float64
 	foodSales := make(map[string]int)
 	drinkSales := make(map[string]int)
 
-	for _, order := range orders {
+	for _, order := range kept {
 		totalRevenue += order.TotalPrice
 		orderValues = append(orderValues, order.TotalPrice)
 		hourStr := fmt.Sprintf("%02d", order.CreatedAt.Hour())
@@ -241,12 +270,13 @@ func generateOrderReport(collection *mongo.Collection, startDate, endDate time.T
 	topDrinks := getTopN(drinkSales, 3)
 	var monthlyOrders map[string]int
 	if period == "Yearly" {
-		monthlyOrders = getMonthlyOrderCounts(orders)
+		monthlyOrders = getMonthlyOrderCounts(kept)
 	}
 
 	fmt.Printf("\n📌 %s Order Report\n", period)
 	fmt.Printf("Total Orders: %d | Total Revenue: $%.2f\n", totalOrders, totalRevenue)
 	fmt.Printf("Order Value Distribution: Min=$%.2f, Max=$%.2f, Avg=$%.2f\n", minVal, maxVal, avgVal)
+	fmt.Printf("Voided: %d orders ($%.2f) by reason %v\n", voids["count"], voids["value"], voids["by_reason"])
 	fmt.Println("Peak Sales Hours:")
 	var hrs []string
 	for h := range peakHours {
@@ -333,7 +363,8 @@ func generateEmployeePerformanceReport(attendanceCollection, orderCollection *mo
 	employeeOrders := make(map[string]int)
 	employeeRevenue := make(map[string]float64)
 	employeeOrderTimestamps := make(map[string][]time.Time)
-	for _, order := range orders {
+	kept, _ := splitVoided(orders)
+	for _, order := range kept {
 		empID := order.EmployeeId.Hex()
 		employeeOrders[empID]++
 		employeeRevenue[empID] += order.TotalPrice
@@ -492,7 +523,7 @@ func generateOperationalEfficiencyReport(attendanceCollection, orderCollection *
 }
 
 // generateRevenueFinancialReport produces a revenue & financial report.
-func generateRevenueFinancialReport(orderCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]interface{} {
+func generateRevenueFinancialReport(orderCollection, paymentCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]interface{} {
 	log.Printf("Generating %s Revenue & Financial Report...\n", period)
 	filter := bson.M{"created_at": bson.M{"$gte": startDate, "$lt": endDate}}
 	cursor, err := orderCollection.Find(context.TODO(), filter)
@@ -505,14 +536,29 @@ func generateRevenueFinancialReport(orderCollection *mongo.Collection, startDate
 		log.Fatalf("Error decoding orders for %s revenue report: %v", period, err)
 	}
 
+	orders, voided := splitVoided(orders)
+	voids := voidSummary(voided)
 	totalRevenue := 0.0
 	orderCount := len(orders)
 	var orderValues []float64
 	totalFoodItems := 0
 	totalDrinkItems := 0
+	var netSales, taxTotal, discountTotal, serviceChargeTotal, deliveryFeeTotal float64
+	orderTypes := make(map[string]map[string]float64)
+	for _, orderType := range []string{models.OrderDineIn, models.OrderTakeaway, models.OrderDelivery} {
+		orderTypes[orderType] = map[string]float64{"count": 0, "revenue": 0}
+	}
 
 	for _, order := range orders {
+		byType := orderTypes[order.OrderType()]
+		byType["count"]++
+		byType["revenue"] += order.TotalPrice
+		deliveryFeeTotal += order.DeliveryFee
 		totalRevenue += order.TotalPrice
+		netSales += order.NetSales
+		taxTotal += order.TaxTotal
+		discountTotal += order.DiscountTotal
+		serviceChargeTotal += order.ServiceCharge
 		orderValues = append(orderValues, order.TotalPrice)
 		totalFoodItems += len(order.Foods)
 		totalDrinkItems += len(order.Drinks)
@@ -537,6 +583,8 @@ func generateRevenueFinancialReport(orderCollection *mongo.Collection, startDate
 		log.Printf("No order records found for %s Revenue Report\n", period)
 	}
 
+	paymentMethods := paymentMethodBreakdown(paymentCollection, startDate, endDate, period)
+
 	totalItems := totalFoodItems + totalDrinkItems
 	var foodShare, drinkShare float64
 	if totalItems > 0 {
@@ -550,15 +598,35 @@ func generateRevenueFinancialReport(orderCollection *mongo.Collection, startDate
 
 	fmt.Printf("\n💰 %s Revenue & Financial Report\n", period)
 	fmt.Printf("Total Revenue: $%.2f | Total Orders: %d\n", totalRevenue, orderCount)
+	fmt.Printf("Net Sales: $%.2f | Tax: $%.2f | Discounts: $%.2f | Service Charge: $%.2f\n", netSales, taxTotal, discountTotal, serviceChargeTotal)
+	fmt.Printf("Delivery Fees: $%.2f\n", deliveryFeeTotal)
+	fmt.Println("Revenue by Order Type:")
+	for _, orderType := range []string{models.OrderDineIn, models.OrderTakeaway, models.OrderDelivery} {
+		t := orderTypes[orderType]
+		fmt.Printf("  %s: orders=%.0f, revenue=$%.2f\n", orderType, t["count"], t["revenue"])
+	}
+	fmt.Printf("Voided: %d orders ($%.2f)\n", voids["count"], voids["value"])
 	fmt.Printf("Order Value: Min=$%.2f, Max=$%.2f, Avg=$%.2f\n", minVal, maxVal, avgVal)
 	fmt.Printf("Food vs. Drink Share: Food=%.2f%%, Drink=%.2f%%\n", foodShare, drinkShare)
 	fmt.Printf("Average Items Per Order: %.2f\n", avgItemsPerOrder)
+	fmt.Println("Payments by Method:")
+	for _, method := range []string{models.PaymentCash, models.PaymentCard, models.PaymentMobileMoney, models.PaymentPoints, models.PaymentVoucher} {
+		m := paymentMethods[method]
+		fmt.Printf("  %s: taken=$%.2f, refunded=$%.2f, net=$%.2f\n", method, m["taken"], m["refunded"], m["net"])
+	}
 
 	reportDir := getReportDir(period)
 	csvHeaders := []string{"Metric", "Value"}
 	csvData := [][]string{
 		{"Total Revenue", fmt.Sprintf("%.2f", totalRevenue)},
 		{"Total Orders", strconv.Itoa(orderCount)},
+		{"Net Sales", fmt.Sprintf("%.2f", netSales)},
+		{"Tax", fmt.Sprintf("%.2f", taxTotal)},
+		{"Discounts", fmt.Sprintf("%.2f", discountTotal)},
+		{"Service Charge", fmt.Sprintf("%.2f", serviceChargeTotal)},
+		{"Delivery Fees", fmt.Sprintf("%.2f", deliveryFeeTotal)},
+		{"Voided Orders", fmt.Sprintf("%d", voids["count"])},
+		{"Voided Value", fmt.Sprintf("%.2f", voids["value"])},
 		{"Min Order Value", fmt.Sprintf("%.2f", minVal)},
 		{"Max Order Value", fmt.Sprintf("%.2f", maxVal)},
 		{"Avg Order Value", fmt.Sprintf("%.2f", avgVal)},
@@ -566,17 +634,434 @@ func generateRevenueFinancialReport(orderCollection *mongo.Collection, startDate
 		{"Drink Share (%)", fmt.Sprintf("%.2f", drinkShare)},
 		{"Avg Items Per Order", fmt.Sprintf("%.2f", avgItemsPerOrder)},
 	}
+	for _, orderType := range []string{models.OrderDineIn, models.OrderTakeaway, models.OrderDelivery} {
+		csvData = append(csvData,
+			[]string{"Orders (" + orderType + ")", fmt.Sprintf("%.0f", orderTypes[orderType]["count"])},
+			[]string{"Revenue (" + orderType + ")", fmt.Sprintf("%.2f", orderTypes[orderType]["revenue"])})
+	}
+	for _, method := range []string{models.PaymentCash, models.PaymentCard, models.PaymentMobileMoney, models.PaymentPoints, models.PaymentVoucher} {
+		csvData = append(csvData, []string{"Net Payments (" + method + ")", fmt.Sprintf("%.2f", paymentMethods[method]["net"])})
+	}
 	saveCSVTable(filepath.Join(reportDir, "revenue_financial_"+period+".csv"), csvHeaders, csvData)
 	return map[string]interface{}{
 		"total_revenue":       totalRevenue,
 		"total_orders":        orderCount,
+		"net_sales":           netSales,
+		"tax_total":           taxTotal,
+		"discount_total":      discountTotal,
+		"service_charge":      serviceChargeTotal,
+		"delivery_fees":       deliveryFeeTotal,
+		"order_types":         orderTypes,
 		"min_order_value":     minVal,
 		"max_order_value":     maxVal,
 		"avg_order_value":     avgVal,
 		"food_share_percent":  foodShare,
 		"drink_share_percent": drinkShare,
 		"avg_items_per_order": avgItemsPerOrder,
+		"payment_methods":     paymentMethods,
+		"voided":              voids,
+	}
+}
+
+// splitVoided separates cancelled and voided orders from the ones that count
+// as sales. Orders merged into another check are dropped from both, their
+// lines count on the check they went to.
+func splitVoided(orders []models.Order) (kept, voided []models.Order) {
+	for _, order := range orders {
+		if order.Status == models.OrderMerged {
+			continue
+		}
+		if order.Status == models.OrderCancelled || order.Status == models.OrderVoided {
+			voided = append(voided, order)
+			continue
+		}
+		kept = append(kept, order)
+	}
+	return kept, voided
+}
+
+// voidSummary counts voided orders and their value, in total and per reason.
+func voidSummary(voided []models.Order) map[string]interface{} {
+	value := 0.0
+	byReason := make(map[string]int)
+	valueByReason := make(map[string]float64)
+	for _, order := range voided {
+		reason := "unspecified"
+		if order.Void != nil {
+			reason = order.Void.Reason
+		}
+		value += order.TotalPrice
+		byReason[reason]++
+		valueByReason[reason] += order.TotalPrice
+	}
+	return map[string]interface{}{
+		"count":           len(voided),
+		"value":           value,
+		"by_reason":       byReason,
+		"value_by_reason": valueByReason,
+		"orders":          voided,
+	}
+}
+
+// paymentMethodBreakdown sums, per payment method, the money taken and the
+// refunds given within the period.
+func paymentMethodBreakdown(paymentCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]map[string]float64 {
+	window := bson.M{"$gte": startDate, "$lt": endDate}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"created_at": window},
+		bson.M{"refunds.at": window},
+	}}
+	cursor, err := paymentCollection.Find(context.TODO(), filter)
+	if err != nil {
+		log.Fatalf("Error fetching payments for %s revenue report: %v", period, err)
+	}
+	defer cursor.Close(context.TODO())
+	var payments []models.Payment
+	if err := cursor.All(context.TODO(), &payments); err != nil {
+		log.Fatalf("Error decoding payments for %s revenue report: %v", period, err)
+	}
+
+	breakdown := make(map[string]map[string]float64)
+	for _, method := range []string{models.PaymentCash, models.PaymentCard, models.PaymentMobileMoney, models.PaymentPoints, models.PaymentVoucher} {
+		breakdown[method] = map[string]float64{"taken": 0, "refunded": 0, "net": 0}
+	}
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(startDate) && t.Before(endDate)
+	}
+	for _, payment := range payments {
+		m, ok := breakdown[payment.Method]
+		if !ok {
+			continue
+		}
+		if inPeriod(payment.CreatedAt) {
+			m["taken"] += payment.Amount
+		}
+		for _, refund := range payment.Refunds {
+			if inPeriod(refund.At) {
+				m["refunded"] += refund.Amount
+			}
+		}
+	}
+	for _, m := range breakdown {
+		m["net"] = m["taken"] - m["refunded"]
+	}
+	return breakdown
+}
+
+// generateWaitlistReport measures the walk-in queue: how many parties joined,
+// how long the seated ones waited, how many walked away, and how far the waits
+// quoted at the door were off.
+func generateWaitlistReport(waitlistCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]interface{} {
+	log.Printf("Generating %s Waitlist Report...\n", period)
+	cursor, err := waitlistCollection.Find(context.TODO(), bson.M{"joined_at": bson.M{"$gte": startDate, "$lt": endDate}})
+	if err != nil {
+		log.Fatalf("Error fetching waitlist for %s waitlist report: %v", period, err)
+	}
+	defer cursor.Close(context.TODO())
+	var entries []models.WaitlistEntry
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		log.Fatalf("Error decoding waitlist for %s waitlist report: %v", period, err)
+	}
+
+	seated, walkedAway, waiting := 0, 0, 0
+	var seatedWait, walkAwayWait, quoteError float64
+	for _, entry := range entries {
+		wait := entry.Wait(endDate).Minutes()
+		switch entry.Status {
+		case models.WaitlistSeated:
+			seated++
+			seatedWait += wait
+			quoteError += wait - float64(entry.QuotedMinutes)
+		case models.WaitlistWalkedAway:
+			walkedAway++
+			walkAwayWait += wait
+		default:
+			waiting++
+		}
+	}
+	average := func(total float64, count int) float64 {
+		if count == 0 {
+			return 0
+		}
+		return total / float64(count)
+	}
+	walkAwayRate := 0.0
+	if seated+walkedAway > 0 {
+		walkAwayRate = float64(walkedAway) / float64(seated+walkedAway)
+	}
+
+	fmt.Printf("\n⏳ %s Waitlist Report\n", period)
+	fmt.Printf("Parties: %d, Seated: %d, Walked Away: %d, Average Wait: %.1f min\n",
+		len(entries), seated, walkedAway, average(seatedWait, seated))
+
+	return map[string]interface{}{
+		"parties":                     len(entries),
+		"seated":                      seated,
+		"walked_away":                 walkedAway,
+		"still_waiting":               waiting,
+		"walk_away_rate":              walkAwayRate,
+		"average_wait_minutes":        average(seatedWait, seated),
+		"average_walk_away_minutes":   average(walkAwayWait, walkedAway),
+		"average_quote_error_minutes": average(quoteError, seated),
+	}
+}
+
+// generateCustomerReport looks at the orders linked to a customer: how many
+// of the customers seen came back, and who spent the most. A customer counts
+// as returning with more than one order in the period or a first visit before it.
+func generateCustomerReport(orderCollection, customerCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]interface{} {
+	log.Printf("Generating %s Customer Report...\n", period)
+	orderFilter := bson.M{
+		"created_at": bson.M{"$gte": startDate, "$lt": endDate},
+		"status":     bson.M{"$nin": bson.A{models.OrderCancelled, models.OrderVoided, models.OrderMerged}},
+	}
+	orderCursor, err := orderCollection.Find(context.TODO(), orderFilter)
+	if err != nil {
+		log.Fatalf("Error fetching orders for %s customer report: %v", period, err)
+	}
+	defer orderCursor.Close(context.TODO())
+	var orders []models.Order
+	if err := orderCursor.All(context.TODO(), &orders); err != nil {
+		log.Fatalf("Error decoding orders for %s customer report: %v", period, err)
+	}
+
+	orderCount := make(map[primitive.ObjectID]int)
+	spend := make(map[primitive.ObjectID]float64)
+	var ids bson.A
+	linked := 0
+	for _, order := range orders {
+		if order.CustomerId.IsZero() {
+			continue
+		}
+		linked++
+		if orderCount[order.CustomerId] == 0 {
+			ids = append(ids, order.CustomerId)
+		}
+		orderCount[order.CustomerId]++
+		spend[order.CustomerId] += order.TotalPrice
+	}
+
+	customers := make(map[primitive.ObjectID]models.Customer)
+	if len(ids) > 0 {
+		customerCursor, err := customerCollection.Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			log.Fatalf("Error fetching customers for %s customer report: %v", period, err)
+		}
+		defer customerCursor.Close(context.TODO())
+		var found []models.Customer
+		if err := customerCursor.All(context.TODO(), &found); err != nil {
+			log.Fatalf("Error decoding customers for %s customer report: %v", period, err)
+		}
+		for _, customer := range found {
+			customers[customer.Id] = customer
+		}
+	}
+
+	returning := 0
+	type topCustomer struct {
+		Id     string  `json:"id"`
+		Name   string  `json:"name"`
+		Phone  string  `json:"phone"`
+		Orders int     `json:"orders"`
+		Spend  float64 `json:"spend"`
+	}
+	var top []topCustomer
+	for id, count := range orderCount {
+		customer := customers[id]
+		if count > 1 || (!customer.FirstVisitAt.IsZero() && customer.FirstVisitAt.Before(startDate)) {
+			returning++
+		}
+		top = append(top, topCustomer{Id: id.Hex(), Name: customer.Name, Phone: customer.Phone, Orders: count, Spend: spend[id]})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Spend != top[j].Spend {
+			return top[i].Spend > top[j].Spend
+		}
+		return top[i].Id < top[j].Id
+	})
+	if len(top) > 10 {
+		top = top[:10]
+	}
+	repeatRate, linkedShare := 0.0, 0.0
+	if len(orderCount) > 0 {
+		repeatRate = float64(returning) / float64(len(orderCount))
+	}
+	if len(orders) > 0 {
+		linkedShare = float64(linked) / float64(len(orders))
+	}
+
+	fmt.Printf("\n👥 %s Customer Report\n", period)
+	fmt.Printf("Customers: %d, Returning: %d, Repeat Rate: %.2f\n", len(orderCount), returning, repeatRate)
+	for _, c := range top {
+		fmt.Printf("%-24s %-16s %-8d %-10.2f\n", c.Name, c.Phone, c.Orders, c.Spend)
+	}
+
+	return map[string]interface{}{
+		"customers":            len(orderCount),
+		"returning_customers":  returning,
+		"repeat_customer_rate": repeatRate,
+		"orders_with_customer": linkedShare,
+		"top_customers":        top,
+	}
+}
+
+// generateVoucherReport sums the vouchers sold and redeemed in the period and
+// the value still owed to voucher holders. Outstanding liability is the balance
+// left on vouchers that have not expired; balances that lapsed during the
+// period are reported as expired.
+func generateVoucherReport(voucherCollection, ledgerCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]interface{} {
+	log.Printf("Generating %s Voucher Report...\n", period)
+	cursor, err := ledgerCollection.Find(context.TODO(), bson.M{"at": bson.M{"$gte": startDate, "$lt": endDate}})
+	if err != nil {
+		log.Fatalf("Error fetching voucher ledger for %s voucher report: %v", period, err)
+	}
+	defer cursor.Close(context.TODO())
+	var entries []models.VoucherEntry
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		log.Fatalf("Error decoding voucher ledger for %s voucher report: %v", period, err)
+	}
+	issued, redeemed, refunded := 0.0, 0.0, 0.0
+	issuedCount := 0
+	for _, entry := range entries {
+		switch entry.Kind {
+		case models.VoucherIssued:
+			issuedCount++
+			issued += entry.Amount
+		case models.VoucherRedeemed:
+			redeemed -= entry.Amount
+		case models.VoucherRefunded:
+			refunded += entry.Amount
+		}
+	}
+
+	sumBalance := func(filter bson.M) (float64, int) {
+		cursor, err := voucherCollection.Aggregate(context.TODO(), bson.A{
+			bson.M{"$match": filter},
+			bson.M{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": "$balance"}, "count": bson.M{"$sum": 1}}},
+		})
+		if err != nil {
+			log.Fatalf("Error summing vouchers for %s voucher report: %v", period, err)
+		}
+		var result []struct {
+			Total float64 `bson:"total"`
+			Count int     `bson:"count"`
+		}
+		if err := cursor.All(context.TODO(), &result); err != nil {
+			log.Fatalf("Error decoding voucher totals for %s voucher report: %v", period, err)
+		}
+		if len(result) == 0 {
+			return 0, 0
+		}
+		return result[0].Total, result[0].Count
+	}
+	outstanding, outstandingCount := sumBalance(bson.M{"balance": bson.M{"$gt": 0}, "expires_at": bson.M{"$gt": endDate}})
+	expired, expiredCount := sumBalance(bson.M{"balance": bson.M{"$gt": 0}, "expires_at": bson.M{"$gte": startDate, "$lte": endDate}})
+
+	fmt.Printf("\n🎁 %s Voucher Report\n", period)
+	fmt.Printf("Issued: %d (%.2f), Redeemed: %.2f, Outstanding Liability: %.2f\n", issuedCount, issued, redeemed, outstanding)
+
+	return map[string]interface{}{
+		"issued_count":          issuedCount,
+		"issued_value":          issued,
+		"redeemed_value":        redeemed,
+		"refunded_value":        refunded,
+		"outstanding_liability": outstanding,
+		"outstanding_vouchers":  outstandingCount,
+		"expired_value":         expired,
+		"expired_vouchers":      expiredCount,
+	}
+}
+
+// generateItemSalesReport sums the quantity and revenue of every food, drink
+// and combo sold in the period. Revenue is after line discounts; lines of a
+// combo carry their share of the bundle price, so a combo's revenue shows up
+// on its items as well as on the combo itself.
+func generateItemSalesReport(orderCollection *mongo.Collection, startDate, endDate time.Time, period string) map[string]interface{} {
+	log.Printf("Generating %s Item Sales Report...\n", period)
+	orderFilter := bson.M{
+		"created_at": bson.M{"$gte": startDate, "$lt": endDate},
+		"status":     bson.M{"$nin": bson.A{models.OrderCancelled, models.OrderVoided, models.OrderMerged}},
+	}
+	cursor, err := orderCollection.Find(context.TODO(), orderFilter)
+	if err != nil {
+		log.Fatalf("Error fetching orders for %s item sales report: %v", period, err)
+	}
+	defer cursor.Close(context.TODO())
+	var orders []models.Order
+	if err := cursor.All(context.TODO(), &orders); err != nil {
+		log.Fatalf("Error decoding orders for %s item sales report: %v", period, err)
+	}
+
+	type itemSales struct {
+		Id           string  `json:"id"`
+		Name         string  `json:"name"`
+		Quantity     float64 `json:"quantity"`
+		Revenue      float64 `json:"revenue"`
+		ComboRevenue float64 `json:"combo_revenue"`
+	}
+	tally := func(sales map[string]*itemSales, id, name string, quantity, revenue float64, inCombo bool) {
+		item, ok := sales[id]
+		if !ok {
+			item = &itemSales{Id: id, Name: name}
+			sales[id] = item
+		}
+		item.Quantity += quantity
+		item.Revenue += revenue
+		if inCombo {
+			item.ComboRevenue += revenue
+		}
+	}
+	foods := make(map[string]*itemSales)
+	drinks := make(map[string]*itemSales)
+	combos := make(map[string]*itemSales)
+	for _, order := range orders {
+		for _, food := range order.Foods {
+			tally(foods, food.FoodId.Hex(), food.FoodName, food.Quantity, food.TotalPrice-food.DiscountAmount, food.Combo != nil)
+		}
+		for _, drink := range order.Drinks {
+			tally(drinks, drink.DrinkId.Hex(), drink.DrinkName, drink.Quantity, drink.TotalPrice-drink.DiscountAmount, drink.Combo != nil)
+		}
+		for _, combo := range order.Combos {
+			revenue := combo.TotalPrice
+			for _, food := range order.Foods {
+				if food.Combo != nil && food.Combo.LineId == combo.LineId {
+					revenue -= food.DiscountAmount
+				}
+			}
+			for _, drink := range order.Drinks {
+				if drink.Combo != nil && drink.Combo.LineId == combo.LineId {
+					revenue -= drink.DiscountAmount
+				}
+			}
+			tally(combos, combo.ComboId.Hex(), combo.ComboName, combo.Quantity, revenue, false)
+		}
+	}
+	ranked := func(sales map[string]*itemSales) []itemSales {
+		list := []itemSales{}
+		for _, item := range sales {
+			list = append(list, *item)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Revenue != list[j].Revenue {
+				return list[i].Revenue > list[j].Revenue
+			}
+			return list[i].Id < list[j].Id
+		})
+		return list
+	}
+
+	report := map[string]interface{}{
+		"foods":  ranked(foods),
+		"drinks": ranked(drinks),
+		"combos": ranked(combos),
+	}
+	fmt.Printf("\n🍔 %s Item Sales Report\n", period)
+	for _, kind := range []string{"foods", "drinks", "combos"} {
+		for _, item := range report[kind].([]itemSales) {
+			fmt.Printf("%-6s %-24s %-8.2f %-10.2f\n", kind, item.Name, item.Quantity, item.Revenue)
+		}
 	}
+	return report
 }
 
 // ─── UTILITY FUNCTIONS ─────────────────────────────────────────────
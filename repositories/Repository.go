@@ -1,6 +1,10 @@
 package repositories
 
 import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/yesetoda/kushena/models"
 )
@@ -25,12 +29,44 @@ type RepositoryInterface interface {
 	MonthlyReport()( []byte, error)
 	YearlyReport()( []byte, error)
 
-	CreateOrder(order models.Order) error
+	CreateOrder(order *models.Order) error
 	UpdateOrder(order *models.Order) error
-	DeleteOrder(id string) error
+	PatchOrder(id primitive.ObjectID, version int64, set, unset bson.M) error
+	UpdateOrderStatus(id string, change models.StatusChange) error
+	GetPendingVoids() ([]models.Order, error)
+	GetOrdersByGuestSession(session string) ([]models.Order, error)
 	GetOrderById(id string) (*models.Order, error)
-	GetAllOrders() ([]models.Order, error)
-	GetAllMyOrders(id string) ([]models.Order, error)
+	FindOrders(query models.OrderQuery) (*models.OrderPage, error)
+	GetOpenOrdersByTable(number int) ([]models.Order, error)
+	CreateOrderChange(change *models.OrderChange) error
+	GetOrderHistory(orderId primitive.ObjectID) ([]models.OrderChange, error)
+	SettleBillPart(id string, number int, employeeId primitive.ObjectID, at time.Time) error
+
+	NextSequence(name string) (int64, error)
+	CreateOrderEvent(event *models.OrderEvent) error
+	GetOrderEventsAfter(seq int64) ([]models.OrderEvent, error)
+
+	CreateTable(table *models.Table) error
+	UpdateTable(table *models.Table) error
+	DeleteTable(id string) error
+	GetTableById(id string) (*models.Table, error)
+	GetTableByNumber(number int) (*models.Table, error)
+	GetAllTables() ([]models.Table, error)
+	SetTableStatus(number int, status string) error
+	RotateGuestToken(number int) (int64, error)
+
+	CreatePayment(payment *models.Payment) error
+	GetPaymentById(id string) (*models.Payment, error)
+	GetPaymentsByOrder(orderId primitive.ObjectID) ([]models.Payment, error)
+	AddRefund(id primitive.ObjectID, refunded float64, refund models.Refund) error
+	GetEmployeePayments(employeeId primitive.ObjectID, from, to time.Time) ([]models.Payment, error)
+	CreateReconciliation(reconciliation *models.CashReconciliation) error
+	GetReconciliations(from, to time.Time) ([]models.CashReconciliation, error)
+
+	IssueReceipt(orderId, employeeId primitive.ObjectID) (*models.Receipt, error)
+
+	GetSettings() (*models.Settings, error)
+	UpdateSettings(settings *models.Settings) error
 
 	CreateFood(food models.Food) error
 	UpdateFood(food *models.Food) error
@@ -43,4 +79,62 @@ type RepositoryInterface interface {
 	DeleteDrink(id string) error
 	GetDrinkById(id string) (*models.Drink, error)
 	GetAllDrinks() ([]models.Drink, error)
+
+	ReserveIdempotencyKey(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(id string, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(id string) error
+
+	CreateReservation(reservation *models.Reservation) error
+	UpdateReservation(reservation *models.Reservation) error
+	UpdateReservationStatus(id primitive.ObjectID, change models.StatusChange, tableNumber int) error
+	GetReservationById(id string) (*models.Reservation, error)
+	GetReservations(from, to time.Time) ([]models.Reservation, error)
+	GetOverlappingReservations(tableNumber int, from, to time.Time) ([]models.Reservation, error)
+
+	CreateWaitlistEntry(entry *models.WaitlistEntry) error
+	GetWaitlistEntryById(id string) (*models.WaitlistEntry, error)
+	GetWaitingParties() ([]models.WaitlistEntry, error)
+	MoveWaitlistEntry(id primitive.ObjectID, from string, set, unset bson.M) error
+	AverageTimeToPay(since time.Time) (time.Duration, int, error)
+
+	CreateCustomer(customer *models.Customer) error
+	UpdateCustomerProfile(customer *models.Customer) error
+	GetCustomerById(id string) (*models.Customer, error)
+	GetCustomerByPhone(phone string) (*models.Customer, error)
+	RecordCustomerSpend(id primitive.ObjectID, spend float64, points int, visit bool, at time.Time) error
+	RedeemCustomerPoints(id primitive.ObjectID, points int) error
+	CreateLoyaltyEntry(entry *models.LoyaltyEntry) error
+	GetLoyaltyEntries(customerId primitive.ObjectID) ([]models.LoyaltyEntry, error)
+
+	CreateVoucher(voucher *models.Voucher) error
+	GetVoucherByCode(code string) (*models.Voucher, error)
+	GetVoucherById(id primitive.ObjectID) (*models.Voucher, error)
+	SpendVoucher(id primitive.ObjectID, amount float64, at time.Time) (float64, error)
+	CreditVoucher(id primitive.ObjectID, amount float64) (float64, error)
+	CreateVoucherEntry(entry *models.VoucherEntry) error
+	GetVoucherEntries(voucherId primitive.ObjectID) ([]models.VoucherEntry, error)
+
+	CreateTip(tip *models.Tip) error
+	GetTips(from, to time.Time) ([]models.Tip, error)
+	GetOrderTips(orderId primitive.ObjectID) ([]models.Tip, error)
+	GetAttendanceBetween(from, to time.Time) ([]models.Attendance, error)
+
+	CreatePricingRule(rule *models.PricingRule) error
+	UpdatePricingRule(rule *models.PricingRule) error
+	DeletePricingRule(id string) error
+	GetPricingRuleById(id string) (*models.PricingRule, error)
+	GetPricingRules(activeOnly bool) ([]models.PricingRule, error)
+
+	CreateCombo(combo *models.Combo) error
+	UpdateCombo(combo *models.Combo) error
+	DeleteCombo(id string) error
+	GetComboById(id string) (*models.Combo, error)
+	GetAllCombos() ([]models.Combo, error)
+
+	SetFoodSoldOut(id string, soldOut bool) error
+	SetDrinkSoldOut(id string, soldOut bool) error
+	CreateMenuSchedule(schedule *models.MenuSchedule) error
+	UpdateMenuSchedule(schedule *models.MenuSchedule) error
+	DeleteMenuSchedule(id string) error
+	GetMenuSchedules(activeOnly bool) ([]models.MenuSchedule, error)
 }
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateReservation(reservation *models.Reservation) error {
+	reservation.Id = primitive.NewObjectID()
+	_, err := repo.ReservationCollection.InsertOne(context.Background(), reservation)
+	return err
+}
+
+func (repo *MongoRepository) UpdateReservation(reservation *models.Reservation) error {
+	res, err := repo.ReservationCollection.UpdateOne(context.Background(), bson.M{"_id": reservation.Id}, bson.M{"$set": reservation})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("reservation not found")
+	}
+	return nil
+}
+
+// UpdateReservationStatus moves a reservation on from the status it was read
+// in; it fails if someone else moved it first.
+func (repo *MongoRepository) UpdateReservationStatus(id primitive.ObjectID, change models.StatusChange, tableNumber int) error {
+	set := bson.M{"status": change.To}
+	if tableNumber != 0 {
+		set["table_number"] = tableNumber
+	}
+	res, err := repo.ReservationCollection.UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": change.From},
+		bson.M{"$set": set, "$push": bson.M{"status_history": change}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("reservation not found or status changed")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) GetReservationById(id string) (*models.Reservation, error) {
+	var reservation models.Reservation
+	rid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.ReservationCollection.FindOne(context.Background(), bson.M{"_id": rid}).Decode(&reservation)
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// GetReservations returns the reservations starting in [from, to), earliest first.
+func (repo *MongoRepository) GetReservations(from, to time.Time) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	cursor, err := repo.ReservationCollection.Find(context.Background(),
+		bson.M{"starts_at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetOverlappingReservations returns the active reservations of a table that
+// overlap [from, to).
+func (repo *MongoRepository) GetOverlappingReservations(tableNumber int, from, to time.Time) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	cursor, err := repo.ReservationCollection.Find(context.Background(), bson.M{
+		"table_number": tableNumber,
+		"status":       bson.M{"$in": models.ActiveReservationStatuses},
+		"starts_at":    bson.M{"$lt": to},
+		"ends_at":      bson.M{"$gt": from},
+	}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// GetSettings returns the saved settings, or the defaults if none were saved yet.
+func (repo *MongoRepository) GetSettings() (*models.Settings, error) {
+	settings := models.DefaultSettings()
+	err := repo.SettingsCollection.FindOne(context.Background(), bson.M{"_id": models.SettingsId}).Decode(settings)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.DefaultSettings(), nil
+	}
+	return settings, err
+}
+
+func (repo *MongoRepository) UpdateSettings(settings *models.Settings) error {
+	settings.Id = models.SettingsId
+	_, err := repo.SettingsCollection.ReplaceOne(context.Background(),
+		bson.M{"_id": models.SettingsId}, settings, options.Replace().SetUpsert(true))
+	return err
+}
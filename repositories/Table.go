@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateTable(table *models.Table) error {
+	table.Id = primitive.NewObjectID()
+	_, err := repo.TableCollection.InsertOne(context.Background(), table)
+	return err
+}
+
+func (repo *MongoRepository) UpdateTable(table *models.Table) error {
+	res, err := repo.TableCollection.UpdateOne(context.Background(), bson.M{"_id": table.Id}, bson.M{"$set": table})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("table not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) DeleteTable(id string) error {
+	tid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := repo.TableCollection.DeleteOne(context.Background(), bson.M{"_id": tid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("table not found")
+	}
+	return nil
+}
+
+func (repo *MongoRepository) GetTableById(id string) (*models.Table, error) {
+	var table models.Table
+	tid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.TableCollection.FindOne(context.Background(), bson.M{"_id": tid}).Decode(&table)
+	return &table, err
+}
+
+func (repo *MongoRepository) GetTableByNumber(number int) (*models.Table, error) {
+	var table models.Table
+	err := repo.TableCollection.FindOne(context.Background(), bson.M{"number": number}).Decode(&table)
+	return &table, err
+}
+
+func (repo *MongoRepository) GetAllTables() ([]models.Table, error) {
+	var tables []models.Table
+	cursor, err := repo.TableCollection.Find(context.Background(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &tables); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func (repo *MongoRepository) SetTableStatus(number int, status string) error {
+	res, err := repo.TableCollection.UpdateOne(context.Background(), bson.M{"number": number}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("table not found")
+	}
+	return nil
+}
+
+// RotateGuestToken moves a table to its next guest token generation and
+// returns it; tokens of earlier generations stop working.
+func (repo *MongoRepository) RotateGuestToken(number int) (int64, error) {
+	var table models.Table
+	err := repo.TableCollection.FindOneAndUpdate(context.Background(),
+		bson.M{"number": number},
+		bson.M{"$inc": bson.M{"guest_token_generation": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&table)
+	if err == mongo.ErrNoDocuments {
+		return 0, fmt.Errorf("table not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return table.GuestTokenGeneration, nil
+}
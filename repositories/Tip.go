@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateTip(tip *models.Tip) error {
+	tip.Id = primitive.NewObjectID()
+	_, err := repo.TipCollection.InsertOne(context.Background(), tip)
+	return err
+}
+
+// GetTips returns the tips left in [from, to), oldest first.
+func (repo *MongoRepository) GetTips(from, to time.Time) ([]models.Tip, error) {
+	tips := []models.Tip{}
+	cursor, err := repo.TipCollection.Find(context.Background(),
+		bson.M{"at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &tips); err != nil {
+		return nil, err
+	}
+	return tips, nil
+}
+
+// GetOrderTips returns the tips left on an order.
+func (repo *MongoRepository) GetOrderTips(orderId primitive.ObjectID) ([]models.Tip, error) {
+	tips := []models.Tip{}
+	cursor, err := repo.TipCollection.Find(context.Background(),
+		bson.M{"order_id": orderId},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &tips); err != nil {
+		return nil, err
+	}
+	return tips, nil
+}
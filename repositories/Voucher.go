@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// ErrVoucherCodeTaken is returned when a new voucher's code is already in use.
+var ErrVoucherCodeTaken = errors.New("voucher code already in use")
+
+func (repo *MongoRepository) CreateVoucher(voucher *models.Voucher) error {
+	voucher.Id = primitive.NewObjectID()
+	_, err := repo.VoucherCollection.InsertOne(context.Background(), voucher)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrVoucherCodeTaken
+	}
+	return err
+}
+
+func (repo *MongoRepository) GetVoucherByCode(code string) (*models.Voucher, error) {
+	var voucher models.Voucher
+	err := repo.VoucherCollection.FindOne(context.Background(), bson.M{"code": code}).Decode(&voucher)
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (repo *MongoRepository) GetVoucherById(id primitive.ObjectID) (*models.Voucher, error) {
+	var voucher models.Voucher
+	err := repo.VoucherCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&voucher)
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// SpendVoucher takes amount off a voucher that has not expired at and still
+// holds that much, and returns the balance left.
+func (repo *MongoRepository) SpendVoucher(id primitive.ObjectID, amount float64, at time.Time) (float64, error) {
+	var voucher models.Voucher
+	err := repo.VoucherCollection.FindOneAndUpdate(context.Background(),
+		bson.M{"_id": id, "balance": bson.M{"$gte": amount}, "expires_at": bson.M{"$gt": at}},
+		bson.M{"$inc": bson.M{"balance": -amount}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&voucher)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, fmt.Errorf("voucher not found, expired or short of balance")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return voucher.Balance, nil
+}
+
+// CreditVoucher puts amount back on a voucher and returns the new balance.
+func (repo *MongoRepository) CreditVoucher(id primitive.ObjectID, amount float64) (float64, error) {
+	var voucher models.Voucher
+	err := repo.VoucherCollection.FindOneAndUpdate(context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"balance": amount}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&voucher)
+	if err != nil {
+		return 0, err
+	}
+	return voucher.Balance, nil
+}
+
+func (repo *MongoRepository) CreateVoucherEntry(entry *models.VoucherEntry) error {
+	entry.Id = primitive.NewObjectID()
+	_, err := repo.VoucherLedgerCollection.InsertOne(context.Background(), entry)
+	return err
+}
+
+// GetVoucherEntries returns a voucher's ledger, oldest first.
+func (repo *MongoRepository) GetVoucherEntries(voucherId primitive.ObjectID) ([]models.VoucherEntry, error) {
+	entries := []models.VoucherEntry{}
+	cursor, err := repo.VoucherLedgerCollection.Find(context.Background(),
+		bson.M{"voucher_id": voucherId},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (repo *MongoRepository) CreateWaitlistEntry(entry *models.WaitlistEntry) error {
+	entry.Id = primitive.NewObjectID()
+	_, err := repo.WaitlistCollection.InsertOne(context.Background(), entry)
+	return err
+}
+
+func (repo *MongoRepository) GetWaitlistEntryById(id string) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	eid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.WaitlistCollection.FindOne(context.Background(), bson.M{"_id": eid}).Decode(&entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetWaitingParties returns the parties still waiting, in the order they joined.
+func (repo *MongoRepository) GetWaitingParties() ([]models.WaitlistEntry, error) {
+	entries := []models.WaitlistEntry{}
+	cursor, err := repo.WaitlistCollection.Find(context.Background(),
+		bson.M{"status": models.WaitlistWaiting},
+		options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// MoveWaitlistEntry applies set and unset to an entry that is still in status
+// from; it fails if someone else moved the party first.
+func (repo *MongoRepository) MoveWaitlistEntry(id primitive.ObjectID, from string, set, unset bson.M) error {
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	res, err := repo.WaitlistCollection.UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": from}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("waitlist entry not found or status changed")
+	}
+	return nil
+}
+
+// AverageTimeToPay is the mean time from opening to paying of the dine-in
+// orders paid since the given time, along with how many orders it covers.
+func (repo *MongoRepository) AverageTimeToPay(since time.Time) (time.Duration, int, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"status":     models.OrderPaid,
+			"created_at": bson.M{"$gte": since},
+			"type":       bson.M{"$in": bson.A{models.OrderDineIn, "", nil}},
+		}},
+		bson.M{"$unwind": "$status_history"},
+		bson.M{"$match": bson.M{"status_history.to": models.OrderPaid}},
+		bson.M{"$group": bson.M{
+			"_id":   nil,
+			"avg":   bson.M{"$avg": bson.M{"$subtract": bson.A{"$status_history.at", "$created_at"}}},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	cursor, err := repo.OrderCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	var result []struct {
+		Avg   float64 `bson:"avg"`
+		Count int     `bson:"count"`
+	}
+	if err := cursor.All(context.Background(), &result); err != nil {
+		return 0, 0, err
+	}
+	if len(result) == 0 {
+		return 0, 0, nil
+	}
+	return time.Duration(result[0].Avg) * time.Millisecond, result[0].Count, nil
+}
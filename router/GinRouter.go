@@ -8,26 +8,30 @@ import (
 
 	"github.com/yesetoda/kushena/controllers"
 	"github.com/yesetoda/kushena/infrastructures/auth_services"
+	"github.com/yesetoda/kushena/infrastructures/idempotency_services"
 
 )
 
 type GinRoute struct {
-	Controller controllers.ContollerInterface
-	Auth       auth_services.AuthController
+	Controller  controllers.ContollerInterface
+	Auth        auth_services.AuthController
+	Idempotency idempotency_services.IdempotencyController
 }
 
-func NewGinRoute(controller controllers.ContollerInterface, auth auth_services.AuthController) *GinRoute {
+func NewGinRoute(controller controllers.ContollerInterface, auth auth_services.AuthController, idempotency idempotency_services.IdempotencyController) *GinRoute {
 	return &GinRoute{
-		Controller: controller,
-		Auth:       auth,
+		Controller:  controller,
+		Auth:        auth,
+		Idempotency: idempotency,
 	}
 }
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, Content-Length")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, Content-Length, Idempotency-Key, If-None-Match")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Idempotent-Replayed")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -45,8 +49,19 @@ func (r *GinRoute) Run() error {
 	router.GET("/", r.Controller.Help)
 	router.POST("/employee/login", r.Controller.Login)
 
-	router.POST("/checkin", r.Auth.AuthenticationMiddleware(), r.Controller.CheckIn)
-	router.POST("/checkout", r.Auth.AuthenticationMiddleware(), r.Controller.CheckOut)
+	public := router.Group("/public")
+	{
+		public.GET("/menu", r.Controller.GetPublicMenu)
+	}
+	guest := router.Group("/guest")
+	guest.Use(r.Auth.GuestMiddleware(), r.Idempotency.Middleware())
+	{
+		guest.POST("/order", r.Controller.CreateGuestOrder)
+		guest.GET("/orders", r.Controller.GetGuestOrders)
+	}
+
+	router.POST("/checkin", r.Auth.AuthenticationMiddleware(), r.Idempotency.Middleware(), r.Controller.CheckIn)
+	router.POST("/checkout", r.Auth.AuthenticationMiddleware(), r.Idempotency.Middleware(), r.Controller.CheckOut)
 	router.GET("/attendance", r.Auth.AuthenticationMiddleware(), r.Controller.Attendance)
 	router.GET("/checkstatus", r.Auth.AuthenticationMiddleware(), r.Controller.CheckStatus)
 	router.GET("/todaysworkingtime", r.Auth.AuthenticationMiddleware(), r.Controller.TodaysWorkingTime)
@@ -60,35 +75,117 @@ func (r *GinRoute) Run() error {
 		report.GET("/yearly", r.Controller.YearlyReport)
 	}
 	manager := router.Group("/manage")
-	manager.Use(r.Auth.RoleMiddleware("Manager"))
+	manager.Use(r.Auth.RoleMiddleware("Manager"), r.Idempotency.Middleware())
 	{
 		manager.POST("/employee", r.Controller.CreateEmployee)
 		manager.GET("/employee/:id", r.Controller.GetEmployeeById)
 		manager.PATCH("/employee", r.Controller.UpdateEmployee)
 		manager.DELETE("/employee/:id", r.Controller.DeleteEmployee)
 		manager.GET("/employees", r.Controller.GetAllEmployees)
+
+		manager.POST("/table", r.Controller.CreateTable)
+		manager.PATCH("/table", r.Controller.UpdateTable)
+		manager.DELETE("/table/:id", r.Controller.DeleteTable)
+		manager.GET("/table/:id/qr", r.Controller.GetTableQRCode)
+
+		manager.POST("/payment/:id/refund", r.Controller.RefundPayment)
+		manager.GET("/reconciliations", r.Controller.GetReconciliations)
+		manager.GET("/tips/payout", r.Controller.TipPayout)
+		manager.POST("/voucher", r.Controller.IssueVoucher)
+
+		manager.GET("/voids", r.Controller.GetPendingVoids)
+		manager.POST("/order/:id/void/approve", r.Controller.ApproveVoid)
+		manager.POST("/order/:id/void/reject", r.Controller.RejectVoid)
+
+		manager.GET("/settings", r.Controller.GetSettings)
+		manager.PUT("/settings", r.Controller.UpdateSettings)
+
+		manager.POST("/pricing-rule", r.Controller.CreatePricingRule)
+		manager.PATCH("/pricing-rule", r.Controller.UpdatePricingRule)
+		manager.DELETE("/pricing-rule/:id", r.Controller.DeletePricingRule)
+		manager.GET("/pricing-rule/:id", r.Controller.GetPricingRuleById)
+		manager.GET("/pricing-rules", r.Controller.GetPricingRules)
+
+		manager.POST("/menu-schedule", r.Controller.CreateMenuSchedule)
+		manager.PATCH("/menu-schedule", r.Controller.UpdateMenuSchedule)
+		manager.DELETE("/menu-schedule/:id", r.Controller.DeleteMenuSchedule)
+		manager.GET("/menu-schedules", r.Controller.GetMenuSchedules)
 	}
 	actions := router.Group("/action")
-	actions.Use(r.Auth.AuthenticationMiddleware())
+	actions.Use(r.Auth.AuthenticationMiddleware(), r.Idempotency.Middleware())
 	{
 		actions.POST("/order", r.Controller.CreateOrder)
 		actions.PATCH("/order", r.Controller.UpdateOrder)
-		actions.DELETE("/order/:id", r.Controller.DeleteOrder)
+		actions.POST("/order/:id/transition", r.Controller.TransitionOrder)
+		actions.POST("/order/:id/void", r.Controller.VoidOrder)
 		actions.GET("/order/:id", r.Controller.GetOrderById)
+		actions.GET("/order/:id/history", r.Controller.GetOrderHistory)
 		actions.GET("/orders", r.Controller.GetAllOrders)
+		actions.GET("/orders/stream", r.Controller.StreamOrders)
 		actions.GET("/myorders", r.Controller.GetAllMyOrders)
+		actions.POST("/order/:id/transfer", r.Controller.TransferOrder)
+		actions.POST("/order/:id/driver", r.Controller.AssignDriver)
+		actions.POST("/order/:id/customer", r.Controller.AttachCustomer)
+		actions.GET("/order/:id/courses", r.Controller.GetOrderCourses)
+		actions.POST("/order/:id/course/:course/fire", r.Controller.FireCourse)
+		actions.POST("/order/:id/course/:course/serve", r.Controller.ServeCourse)
+		actions.POST("/order/:id/split", r.Controller.SplitOrder)
+		actions.POST("/order/:id/split/:part/settle", r.Controller.SettleBillPart)
+		actions.POST("/order/:id/payment", r.Controller.RecordPayment)
+		actions.GET("/order/:id/payments", r.Controller.GetOrderPayments)
+		actions.POST("/order/:id/tip", r.Controller.RecordTip)
+		actions.GET("/order/:id/tips", r.Controller.GetOrderTips)
+		actions.GET("/order/:id/receipt", r.Controller.GetReceipt)
+		actions.POST("/reconcile", r.Controller.ReconcileCash)
+
+		actions.GET("/table/:id", r.Controller.GetTableById)
+		actions.GET("/tables", r.Controller.GetAllTables)
+		actions.POST("/tables/merge", r.Controller.MergeTables)
+		actions.POST("/table/:id/guest-token", r.Controller.IssueGuestToken)
+
+		actions.POST("/reservation", r.Controller.CreateReservation)
+		actions.PATCH("/reservation", r.Controller.UpdateReservation)
+		actions.GET("/reservation/:id", r.Controller.GetReservationById)
+		actions.GET("/reservations", r.Controller.GetDailyReservations)
+		actions.POST("/reservation/:id/confirm", r.Controller.ConfirmReservation)
+		actions.POST("/reservation/:id/seat", r.Controller.SeatReservation)
+		actions.POST("/reservation/:id/no-show", r.Controller.NoShowReservation)
+		actions.POST("/reservation/:id/cancel", r.Controller.CancelReservation)
+
+		actions.POST("/waitlist", r.Controller.JoinWaitlist)
+		actions.GET("/waitlist", r.Controller.GetWaitlist)
+		actions.POST("/waitlist/:id/seat", r.Controller.SeatWaitlistParty)
+		actions.POST("/waitlist/:id/walk-away", r.Controller.WalkAwayWaitlistParty)
+
+		actions.POST("/customer", r.Controller.CreateCustomer)
+		actions.PATCH("/customer", r.Controller.UpdateCustomer)
+		actions.GET("/customer", r.Controller.GetCustomerByPhone)
+		actions.GET("/customer/:id", r.Controller.GetCustomerById)
+		actions.GET("/customer/:id/points", r.Controller.GetCustomerPoints)
+
+		actions.GET("/voucher/:code", r.Controller.GetVoucher)
+		actions.GET("/voucher/:code/ledger", r.Controller.GetVoucherLedger)
+		actions.GET("/voucher/:code/qr", r.Controller.GetVoucherQRCode)
 
 		actions.POST("/food", r.Controller.CreateFood)
 		actions.PATCH("/food", r.Controller.UpdateFood)
 		actions.DELETE("/food/:id", r.Controller.DeleteFood)
 		actions.GET("/food/:id", r.Controller.GetFoodById)
 		actions.GET("/foods", r.Controller.GetAllFoods)
+		actions.PUT("/food/:id/availability", r.Controller.SetFoodAvailability)
 
 		actions.POST("/drink", r.Controller.CreateDrink)
 		actions.PATCH("/drink", r.Controller.UpdateDrink)
 		actions.DELETE("/drink/:id", r.Controller.DeleteDrink)
 		actions.GET("/drink/:id", r.Controller.GetDrinkById)
 		actions.GET("/drinks", r.Controller.GetAllDrinks)
+		actions.PUT("/drink/:id/availability", r.Controller.SetDrinkAvailability)
+
+		actions.POST("/combo", r.Controller.CreateCombo)
+		actions.PATCH("/combo", r.Controller.UpdateCombo)
+		actions.DELETE("/combo/:id", r.Controller.DeleteCombo)
+		actions.GET("/combo/:id", r.Controller.GetComboById)
+		actions.GET("/combos", r.Controller.GetAllCombos)
 	}
 
 	router.NoMethod(func(c *gin.Context) {
@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// validateCombo checks that a combo has a price and named slots, each offering
+// foods and drinks that are on the menu.
+func (usecase *UsecaseImplemented) validateCombo(combo *models.Combo) error {
+	if combo.Name == "" {
+		return fmt.Errorf("%w: a combo needs a name", ErrInvalidMenuItem)
+	}
+	if combo.Price < 0 {
+		return fmt.Errorf("%w: combo price cannot be negative", ErrInvalidMenuItem)
+	}
+	if len(combo.Slots) == 0 {
+		return fmt.Errorf("%w: a combo needs at least one slot", ErrInvalidMenuItem)
+	}
+	seen := make(map[string]bool)
+	for _, slot := range combo.Slots {
+		if slot.Name == "" || seen[slot.Name] {
+			return fmt.Errorf("%w: combo slot names must be set and unique", ErrInvalidMenuItem)
+		}
+		seen[slot.Name] = true
+		if len(slot.FoodIds) == 0 && len(slot.DrinkIds) == 0 {
+			return fmt.Errorf("%w: slot %q offers nothing", ErrInvalidMenuItem, slot.Name)
+		}
+		for _, id := range slot.FoodIds {
+			if _, err := usecase.Repo.GetFoodById(id.Hex()); err != nil {
+				return fmt.Errorf("%w: food %s", ErrItemNotFound, id.Hex())
+			}
+		}
+		for _, id := range slot.DrinkIds {
+			if _, err := usecase.Repo.GetDrinkById(id.Hex()); err != nil {
+				return fmt.Errorf("%w: drink %s", ErrItemNotFound, id.Hex())
+			}
+		}
+	}
+	combo.Price = roundMoney(combo.Price)
+	return nil
+}
+
+func (usecase *UsecaseImplemented) CreateCombo(combo *models.Combo) error {
+	if err := usecase.validateCombo(combo); err != nil {
+		return err
+	}
+	return usecase.Repo.CreateCombo(combo)
+}
+
+func (usecase *UsecaseImplemented) UpdateCombo(combo *models.Combo) error {
+	if err := usecase.validateCombo(combo); err != nil {
+		return err
+	}
+	if err := usecase.Repo.UpdateCombo(combo); err != nil {
+		return fmt.Errorf("%w: combo %s", ErrItemNotFound, combo.Id.Hex())
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) DeleteCombo(id string) error {
+	return usecase.Repo.DeleteCombo(id)
+}
+
+func (usecase *UsecaseImplemented) GetComboById(id string) (*models.Combo, error) {
+	return usecase.Repo.GetComboById(id)
+}
+
+func (usecase *UsecaseImplemented) GetAllCombos() ([]models.Combo, error) {
+	return usecase.Repo.GetAllCombos()
+}
+
+func containsId(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// expandCombos checks the choices of every combo on an order against its menu
+// entry and replaces the order's combo lines with one line per choice. Lines
+// already fired or served keep their times and are not held again, and a
+// combo already on the order before keeps its bundle price.
+func (usecase *UsecaseImplemented) expandCombos(order, before *models.Order) error {
+	type times struct{ fired, served time.Time }
+	kept := make(map[string]times)
+	storedCombos := make(map[primitive.ObjectID]models.ComboOrder)
+	if before != nil {
+		for _, combo := range before.Combos {
+			storedCombos[combo.LineId] = combo
+		}
+	}
+	partKey := func(part *models.ComboPart) string { return part.LineId.Hex() + "/" + part.Slot }
+
+	var foods []models.FoodOrder
+	for _, line := range order.Foods {
+		if line.Combo == nil {
+			foods = append(foods, line)
+			continue
+		}
+		kept[partKey(line.Combo)] = times{line.FiredAt, line.ServedAt}
+	}
+	var drinks []models.DrinkOrder
+	for _, line := range order.Drinks {
+		if line.Combo == nil {
+			drinks = append(drinks, line)
+			continue
+		}
+		kept[partKey(line.Combo)] = times{line.FiredAt, line.ServedAt}
+	}
+
+	for i := range order.Combos {
+		ordered := &order.Combos[i]
+		if ordered.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be positive for combo %s", ErrInvalidOrder, ordered.ComboId.Hex())
+		}
+		combo, err := usecase.Repo.GetComboById(ordered.ComboId.Hex())
+		if err != nil {
+			return fmt.Errorf("%w: combo %s", ErrItemNotFound, ordered.ComboId.Hex())
+		}
+		if len(ordered.Note) > maxNoteLength {
+			return fmt.Errorf("%w: note on %s is longer than %d characters", ErrInvalidOrder, combo.Name, maxNoteLength)
+		}
+		if len(ordered.Choices) != len(combo.Slots) {
+			return fmt.Errorf("%w: %s needs one choice for each of its %d slots", ErrInvalidOrder, combo.Name, len(combo.Slots))
+		}
+		if ordered.LineId.IsZero() {
+			ordered.LineId = primitive.NewObjectID()
+		}
+		ordered.ComboName = combo.Name
+		ordered.Price = combo.Price
+		if stored, ok := storedCombos[ordered.LineId]; ok && stored.ComboId == ordered.ComboId {
+			ordered.Price = stored.Price
+		}
+
+		for _, slot := range combo.Slots {
+			var choice *models.ComboChoice
+			for c := range ordered.Choices {
+				if ordered.Choices[c].Slot == slot.Name {
+					choice = &ordered.Choices[c]
+					break
+				}
+			}
+			if choice == nil {
+				return fmt.Errorf("%w: %s needs a choice for %s", ErrInvalidOrder, combo.Name, slot.Name)
+			}
+			part := &models.ComboPart{LineId: ordered.LineId, ComboId: combo.Id, ComboName: combo.Name, Slot: slot.Name}
+			at := kept[partKey(part)]
+			// a line already fired is never held again
+			held := ordered.Held && at.fired.IsZero()
+			switch {
+			case !choice.FoodId.IsZero() && containsId(slot.FoodIds, choice.FoodId):
+				foods = append(foods, models.FoodOrder{
+					FoodId:    choice.FoodId,
+					Quantity:  ordered.Quantity,
+					Seat:      ordered.Seat,
+					Modifiers: choice.Modifiers,
+					Note:      ordered.Note,
+					Course:    ordered.Course,
+					Held:      held,
+					FiredAt:   at.fired,
+					ServedAt:  at.served,
+					Combo:     part,
+				})
+			case !choice.DrinkId.IsZero() && containsId(slot.DrinkIds, choice.DrinkId):
+				drinks = append(drinks, models.DrinkOrder{
+					DrinkId:   choice.DrinkId,
+					Quantity:  ordered.Quantity,
+					Seat:      ordered.Seat,
+					Variant:   choice.Variant,
+					Modifiers: choice.Modifiers,
+					Note:      ordered.Note,
+					Course:    ordered.Course,
+					Held:      held,
+					FiredAt:   at.fired,
+					ServedAt:  at.served,
+					Combo:     part,
+				})
+			default:
+				return fmt.Errorf("%w: %s is not offered for %s of %s", ErrInvalidOrder, choiceItem(choice), slot.Name, combo.Name)
+			}
+		}
+	}
+	order.Foods = foods
+	order.Drinks = drinks
+	return nil
+}
+
+func choiceItem(choice *models.ComboChoice) string {
+	if !choice.FoodId.IsZero() {
+		return "food " + choice.FoodId.Hex()
+	}
+	return "drink " + choice.DrinkId.Hex()
+}
+
+// comboShare is a priced line of a combo as shareComboPrice sees it.
+type comboShare struct {
+	part       *models.ComboPart
+	price      *float64
+	totalPrice *float64
+	quantity   float64
+	extras     float64
+	share      float64
+}
+
+// shareComboPrice spreads each combo's bundle price over its priced lines in
+// proportion to their menu prices, so item revenue adds up to what the guest
+// paid. Shares are in cents and the cent left over from rounding goes to the
+// dearest line; modifiers are charged on top of the share.
+func shareComboPrice(order *models.Order) {
+	byCombo := make(map[primitive.ObjectID][]comboShare)
+	add := func(part *models.ComboPart, price, totalPrice *float64, quantity float64, modifiers []models.ChosenModifier) {
+		extras := modifierExtras(modifiers)
+		part.ListPrice = roundMoney(*price - extras)
+		byCombo[part.LineId] = append(byCombo[part.LineId], comboShare{part: part, price: price, totalPrice: totalPrice, quantity: quantity, extras: extras})
+	}
+	for i := range order.Foods {
+		if line := &order.Foods[i]; line.Combo != nil {
+			add(line.Combo, &line.Price, &line.TotalPrice, line.Quantity, line.Modifiers)
+		}
+	}
+	for i := range order.Drinks {
+		if line := &order.Drinks[i]; line.Combo != nil {
+			add(line.Combo, &line.Price, &line.TotalPrice, line.Quantity, line.Modifiers)
+		}
+	}
+
+	for i := range order.Combos {
+		combo := &order.Combos[i]
+		lines := byCombo[combo.LineId]
+		if len(lines) == 0 {
+			continue
+		}
+		listTotal := 0.0
+		for _, line := range lines {
+			listTotal += line.part.ListPrice
+		}
+		shared, dearest := 0.0, 0
+		for j := range lines {
+			if listTotal > 0 {
+				lines[j].share = roundMoney(combo.Price * lines[j].part.ListPrice / listTotal)
+			} else {
+				lines[j].share = roundMoney(combo.Price / float64(len(lines)))
+			}
+			shared += lines[j].share
+			if lines[j].part.ListPrice > lines[dearest].part.ListPrice {
+				dearest = j
+			}
+		}
+		lines[dearest].share = roundMoney(lines[dearest].share + combo.Price - shared)
+
+		combo.TotalPrice = 0
+		for _, line := range lines {
+			*line.price = roundMoney(line.share + line.extras)
+			*line.totalPrice = *line.price * line.quantity
+			combo.TotalPrice += *line.totalPrice
+		}
+		combo.TotalPrice = roundMoney(combo.TotalPrice)
+	}
+}
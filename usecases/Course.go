@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// prepareCourses checks course numbers and stamps lines that are not held as
+// fired, so they reach the kitchen and bar straight away. Held lines carry no
+// fire or serve time.
+func prepareCourses(order *models.Order, now time.Time) error {
+	for i := range order.Foods {
+		line := &order.Foods[i]
+		if line.Course < 0 {
+			return fmt.Errorf("%w: course of %s cannot be negative", ErrInvalidCourse, line.FoodId.Hex())
+		}
+		if line.Held {
+			line.FiredAt, line.ServedAt = time.Time{}, time.Time{}
+		} else if line.FiredAt.IsZero() {
+			line.FiredAt = now
+		}
+	}
+	for i := range order.Drinks {
+		line := &order.Drinks[i]
+		if line.Course < 0 {
+			return fmt.Errorf("%w: course of %s cannot be negative", ErrInvalidCourse, line.DrinkId.Hex())
+		}
+		if line.Held {
+			line.FiredAt, line.ServedAt = time.Time{}, time.Time{}
+		} else if line.FiredAt.IsZero() {
+			line.FiredAt = now
+		}
+	}
+	return nil
+}
+
+// FireCourse releases the held lines of a course to the kitchen and bar.
+func (usecase *UsecaseImplemented) FireCourse(id string, course int, employeeId primitive.ObjectID) (*models.Order, error) {
+	return usecase.updateCourse(id, course, employeeId, models.EventCourseFired, func(held bool, firedAt, servedAt *time.Time, now time.Time) bool {
+		if !held {
+			return false
+		}
+		*firedAt = now
+		return true
+	})
+}
+
+// ServeCourse marks the fired lines of a course as served.
+func (usecase *UsecaseImplemented) ServeCourse(id string, course int, employeeId primitive.ObjectID) (*models.Order, error) {
+	return usecase.updateCourse(id, course, employeeId, models.EventOrderUpdated, func(held bool, firedAt, servedAt *time.Time, now time.Time) bool {
+		if held || !servedAt.IsZero() {
+			return false
+		}
+		*servedAt = now
+		return true
+	})
+}
+
+// GetOrderCourses returns the course view of an order.
+func (usecase *UsecaseImplemented) GetOrderCourses(id string) ([]models.CourseView, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return order.Courses(), nil
+}
+
+// updateCourse applies step to every line of a course and saves the order if
+// any line changed. step reports whether it changed the line.
+func (usecase *UsecaseImplemented) updateCourse(id string, course int, employeeId primitive.ObjectID, eventType string,
+	step func(held bool, firedAt, servedAt *time.Time, now time.Time) bool) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+
+	before := *order
+	order.Foods = append([]models.FoodOrder(nil), order.Foods...)
+	order.Drinks = append([]models.DrinkOrder(nil), order.Drinks...)
+	now := time.Now().UTC()
+	changed := 0
+	for i := range order.Foods {
+		line := &order.Foods[i]
+		if line.Course == course && step(line.Held, &line.FiredAt, &line.ServedAt, now) {
+			line.Held = false
+			changed++
+		}
+	}
+	for i := range order.Drinks {
+		line := &order.Drinks[i]
+		if line.Course == course && step(line.Held, &line.FiredAt, &line.ServedAt, now) {
+			line.Held = false
+			changed++
+		}
+	}
+	if changed == 0 {
+		return nil, fmt.Errorf("%w: nothing to do for course %d", ErrInvalidCourse, course)
+	}
+	// combos of a fired course stay fired when their lines are rebuilt
+	order.Combos = append([]models.ComboOrder(nil), order.Combos...)
+	for i := range order.Combos {
+		if order.Combos[i].Course == course {
+			order.Combos[i].Held = false
+		}
+	}
+
+	if err := usecase.Repo.UpdateOrder(order); err != nil {
+		return nil, orderWriteError(err)
+	}
+	usecase.recordChange(&before, order, employeeId)
+	usecase.publishOrderEvent(eventType, *order)
+	return order, nil
+}
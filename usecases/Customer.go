@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+	"github.com/yesetoda/kushena/repositories"
+)
+
+// normalizePhone drops the spaces, dashes, dots and brackets people type in
+// phone numbers so the same number always finds the same customer.
+func normalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, phone)
+}
+
+func customerWriteError(err error) error {
+	if errors.Is(err, repositories.ErrPhoneTaken) {
+		return ErrCustomerExists
+	}
+	if err != nil && err.Error() == "customer not found" {
+		return ErrCustomerNotFound
+	}
+	return err
+}
+
+func (usecase *UsecaseImplemented) CreateCustomer(customer *models.Customer) error {
+	customer.Phone = normalizePhone(customer.Phone)
+	if customer.Phone == "" || customer.Name == "" {
+		return fmt.Errorf("%w: name and phone are required", ErrInvalidCustomer)
+	}
+	customer.Visits = 0
+	customer.LifetimeSpend = 0
+	customer.Points = 0
+	customer.FirstVisitAt = time.Time{}
+	customer.LastVisitAt = time.Time{}
+	customer.CreatedAt = time.Now().UTC()
+	return customerWriteError(usecase.Repo.CreateCustomer(customer))
+}
+
+// UpdateCustomer changes a customer's name, phone and birthday.
+func (usecase *UsecaseImplemented) UpdateCustomer(customer *models.Customer) (*models.Customer, error) {
+	customer.Phone = normalizePhone(customer.Phone)
+	if customer.Phone == "" || customer.Name == "" {
+		return nil, fmt.Errorf("%w: name and phone are required", ErrInvalidCustomer)
+	}
+	if err := customerWriteError(usecase.Repo.UpdateCustomerProfile(customer)); err != nil {
+		return nil, err
+	}
+	return usecase.GetCustomerById(customer.Id.Hex())
+}
+
+func (usecase *UsecaseImplemented) GetCustomerById(id string) (*models.Customer, error) {
+	customer, err := usecase.Repo.GetCustomerById(id)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+func (usecase *UsecaseImplemented) GetCustomerByPhone(phone string) (*models.Customer, error) {
+	customer, err := usecase.Repo.GetCustomerByPhone(normalizePhone(phone))
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// GetCustomerPoints returns a customer's points ledger, newest first.
+func (usecase *UsecaseImplemented) GetCustomerPoints(id string) ([]models.LoyaltyEntry, error) {
+	customer, err := usecase.GetCustomerById(id)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.Repo.GetLoyaltyEntries(customer.Id)
+}
+
+// AttachCustomer links an open order to the customer with the given phone
+// number, so its payments count towards the customer's visits and points.
+func (usecase *UsecaseImplemented) AttachCustomer(orderId, phone string, employeeId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(orderId)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	customer, err := usecase.GetCustomerByPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) > 0 && order.CustomerId != customer.Id {
+		return nil, fmt.Errorf("%w: payments were already taken for another customer", ErrInvalidOrder)
+	}
+	if err := usecase.Repo.PatchOrder(order.Id, order.Version, bson.M{"customer_id": customer.Id}, nil); err != nil {
+		return nil, orderWriteError(err)
+	}
+	before := *order
+	order.CustomerId = customer.Id
+	order.Version++
+	usecase.recordChange(&before, order, employeeId)
+	return order, nil
+}
+
+// checkCustomer confirms that a customer set on a new order exists.
+func (usecase *UsecaseImplemented) checkCustomer(customerId primitive.ObjectID) error {
+	if customerId.IsZero() {
+		return nil
+	}
+	if _, err := usecase.Repo.GetCustomerById(customerId.Hex()); err != nil {
+		return ErrCustomerNotFound
+	}
+	return nil
+}
+
+// redeemPoints turns the points offered on a loyalty_points payment into an
+// amount, capped at what is due, and takes the points needed for it off the
+// customer's balance.
+func (usecase *UsecaseImplemented) redeemPoints(order *models.Order, request models.PaymentRequest, due float64, loyalty models.LoyaltySettings) (int, float64, error) {
+	if loyalty.PointValue <= 0 {
+		return 0, 0, fmt.Errorf("%w: the loyalty program does not redeem points", ErrInvalidPayment)
+	}
+	if order.CustomerId.IsZero() {
+		return 0, 0, fmt.Errorf("%w: attach a customer before paying with points", ErrInvalidPayment)
+	}
+	if request.Points <= 0 {
+		return 0, 0, fmt.Errorf("%w: points to redeem must be positive", ErrInvalidPayment)
+	}
+	customer, err := usecase.Repo.GetCustomerById(order.CustomerId.Hex())
+	if err != nil {
+		return 0, 0, ErrCustomerNotFound
+	}
+	if customer.Points < loyalty.MinRedeemPoints {
+		return 0, 0, fmt.Errorf("%w: points can be redeemed from %d, customer has %d", ErrInvalidPayment, loyalty.MinRedeemPoints, customer.Points)
+	}
+
+	amount := roundMoney(math.Min(float64(request.Points)*loyalty.PointValue, due))
+	points := int(math.Ceil(amount/loyalty.PointValue - 1e-9))
+	if err := usecase.Repo.RedeemCustomerPoints(customer.Id, points); err != nil {
+		return 0, 0, fmt.Errorf("%w: customer has %d points", ErrInvalidPayment, customer.Points)
+	}
+	return points, amount, nil
+}
+
+// settleLoyalty books a recorded payment to the order's customer: the first
+// payment on an order counts as a visit, money paid adds to lifetime spend and
+// earns points, and points spent are written to the ledger.
+func (usecase *UsecaseImplemented) settleLoyalty(order *models.Order, payment *models.Payment, firstPayment bool) {
+	if order.CustomerId.IsZero() {
+		return
+	}
+	spend := payment.Amount
+	if payment.Method == models.PaymentPoints {
+		spend = 0
+	}
+	if err := usecase.Repo.RecordCustomerSpend(order.CustomerId, spend, payment.PointsEarned, firstPayment, payment.CreatedAt); err != nil {
+		log.Printf("failed to credit customer %s for payment %s: %v", order.CustomerId.Hex(), payment.Id.Hex(), err)
+	}
+	switch {
+	case payment.Method == models.PaymentPoints:
+		usecase.writeLoyaltyEntry(order.CustomerId, payment, models.PointsRedeemed, -payment.Points, payment.EmployeeId, payment.CreatedAt)
+	case payment.PointsEarned > 0:
+		usecase.writeLoyaltyEntry(order.CustomerId, payment, models.PointsEarned, payment.PointsEarned, payment.EmployeeId, payment.CreatedAt)
+	}
+}
+
+// reverseLoyalty undoes the share of a payment's loyalty effect that a refund
+// covers: points spent are given back, and spend and points earned are taken
+// back.
+func (usecase *UsecaseImplemented) reverseLoyalty(payment *models.Payment, refund models.Refund) {
+	if payment.Amount <= 0 || (payment.Points == 0 && payment.PointsEarned == 0) {
+		return
+	}
+	order, err := usecase.Repo.GetOrderById(payment.OrderId.Hex())
+	if err != nil || order.CustomerId.IsZero() {
+		return
+	}
+	share := refund.Amount / payment.Amount
+	kind, points, spend := models.PointsReversed, -int(math.Floor(float64(payment.PointsEarned)*share)), -refund.Amount
+	if payment.Method == models.PaymentPoints {
+		kind, points, spend = models.PointsReturned, int(math.Round(float64(payment.Points)*share)), 0
+	}
+	if err := usecase.Repo.RecordCustomerSpend(order.CustomerId, spend, points, false, refund.At); err != nil {
+		log.Printf("failed to reverse loyalty of payment %s: %v", payment.Id.Hex(), err)
+		return
+	}
+	if points != 0 {
+		usecase.writeLoyaltyEntry(order.CustomerId, payment, kind, points, refund.EmployeeId, refund.At)
+	}
+}
+
+func (usecase *UsecaseImplemented) writeLoyaltyEntry(customerId primitive.ObjectID, payment *models.Payment, kind string, points int, employeeId primitive.ObjectID, at time.Time) {
+	entry := models.LoyaltyEntry{
+		CustomerId: customerId,
+		OrderId:    payment.OrderId,
+		PaymentId:  payment.Id,
+		Kind:       kind,
+		Points:     points,
+		EmployeeId: employeeId,
+		At:         at,
+	}
+	if err := usecase.Repo.CreateLoyaltyEntry(&entry); err != nil {
+		log.Printf("failed to write loyalty entry for payment %s: %v", payment.Id.Hex(), err)
+	}
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/infrastructures/token_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+// IssueGuestToken rotates a table's guest token and returns the new one with
+// the menu link that carries it. Tokens handed out before stop working.
+func (usecase *UsecaseImplemented) IssueGuestToken(tableId string) (*models.GuestToken, error) {
+	table, err := usecase.Repo.GetTableById(tableId)
+	if err != nil {
+		return nil, ErrTableNotFound
+	}
+	generation, err := usecase.Repo.RotateGuestToken(table.Number)
+	if err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := token_services.GenerateGuestToken(table.Number, generation)
+	if err != nil {
+		return nil, err
+	}
+	url, err := usecase.QR.MenuURL(table.Number, token)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GuestToken{Table: table.Number, Token: token, URL: url, ExpiresAt: expiresAt}, nil
+}
+
+// rotateGuestToken cuts off the guest token of a table whose party has left.
+func (usecase *UsecaseImplemented) rotateGuestToken(number int) {
+	if _, err := usecase.Repo.RotateGuestToken(number); err != nil {
+		log.Printf("failed to rotate guest token of table %d: %v", number, err)
+	}
+}
+
+// CheckGuestToken confirms a guest token still belongs to its table's current
+// generation.
+func (usecase *UsecaseImplemented) CheckGuestToken(claims *models.GuestClaims) error {
+	table, err := usecase.Repo.GetTableByNumber(claims.Table)
+	if err != nil {
+		return ErrTableNotFound
+	}
+	if table.GuestTokenGeneration != claims.Generation {
+		return ErrGuestTokenExpired
+	}
+	return nil
+}
+
+// CreateGuestOrder places a dine-in order for the guest's table. It waits for
+// a waiter to confirm it and belongs to the waiter assigned to the table.
+func (usecase *UsecaseImplemented) CreateGuestOrder(claims *models.GuestClaims, order models.Order) (*models.Order, error) {
+	if err := usecase.CheckGuestToken(claims); err != nil {
+		return nil, err
+	}
+	table, err := usecase.Repo.GetTableByNumber(claims.Table)
+	if err != nil {
+		return nil, ErrTableNotFound
+	}
+	if table.WaiterId.IsZero() {
+		return nil, fmt.Errorf("%w: no waiter is serving table %d yet", ErrInvalidTable, table.Number)
+	}
+
+	// guests order for their own table and nothing else
+	order.Type = models.OrderDineIn
+	order.TableNumber = table.Number
+	order.EmployeeId = table.WaiterId
+	order.Source = models.SourceGuest
+	order.GuestSession = claims.Id
+	order.CustomerId = primitive.NilObjectID
+	order.Discount = nil
+	order.Delivery = nil
+	order.PickupAt = time.Time{}
+	for i := range order.Foods {
+		order.Foods[i].Discount = nil
+	}
+	for i := range order.Drinks {
+		order.Drinks[i].Discount = nil
+	}
+	return usecase.createOrder(order, models.OrderNeedsConfirmation, primitive.NilObjectID)
+}
+
+// GetGuestOrders returns the orders placed with the guest's token.
+func (usecase *UsecaseImplemented) GetGuestOrders(claims *models.GuestClaims) ([]models.Order, error) {
+	if err := usecase.CheckGuestToken(claims); err != nil {
+		return nil, err
+	}
+	return usecase.Repo.GetOrdersByGuestSession(claims.Id)
+}
@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"log"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// BeginIdempotentRequest claims an idempotency key for a request. It returns
+// nil when the request should run, or the stored record when a finished
+// request with the same key should be replayed. A key reused for a different
+// request, or for one still running, is an error.
+func (usecase *UsecaseImplemented) BeginIdempotentRequest(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	record.State = models.IdempotencyInProgress
+	existing, err := usecase.Repo.ReserveIdempotencyKey(record)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if existing.RequestHash != record.RequestHash {
+		return nil, ErrIdempotencyMismatch
+	}
+	if existing.State != models.IdempotencyDone {
+		return nil, ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+// FinishIdempotentRequest stores the response for replay. Server errors are
+// not stored so the client can retry with the same key.
+func (usecase *UsecaseImplemented) FinishIdempotentRequest(id string, statusCode int, contentType string, body []byte) {
+	var err error
+	if statusCode >= 500 {
+		err = usecase.Repo.ReleaseIdempotencyKey(id)
+	} else {
+		err = usecase.Repo.CompleteIdempotencyKey(id, statusCode, contentType, body)
+	}
+	if err != nil {
+		log.Printf("failed to finish idempotency key %s: %v", id, err)
+	}
+}
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/infrastructures/receipt_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+// uncategorized is the category of items saved without one.
+const uncategorized = "Other"
+
+// GetPublicMenu returns the foods, drinks and combos guests can order now,
+// grouped by category, with categories and items in name order. Sold out and
+// off-schedule items are left out, and so are combos with a slot left empty.
+func (usecase *UsecaseImplemented) GetPublicMenu() (*models.Menu, error) {
+	foods, err := usecase.GetAllFoods(false)
+	if err != nil {
+		return nil, err
+	}
+	drinks, err := usecase.GetAllDrinks(false)
+	if err != nil {
+		return nil, err
+	}
+	combos, err := usecase.Repo.GetAllCombos()
+	if err != nil {
+		return nil, err
+	}
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	offered := make(map[primitive.ObjectID]bool)
+	for _, food := range foods {
+		offered[food.Id] = true
+	}
+	for _, drink := range drinks {
+		offered[drink.Id] = true
+	}
+
+	byCategory := make(map[string][]models.MenuItem)
+	add := func(category string, item models.MenuItem) {
+		if category == "" {
+			category = uncategorized
+		}
+		byCategory[category] = append(byCategory[category], item)
+	}
+	for _, food := range foods {
+		add(food.Category, models.MenuItem{
+			Id:             food.Id,
+			Kind:           "food",
+			Name:           food.Name,
+			Description:    food.Description,
+			Image:          food.Image,
+			Price:          food.Price,
+			ModifierGroups: food.ModifierGroups,
+		})
+	}
+	for _, drink := range drinks {
+		add(drink.Category, models.MenuItem{
+			Id:             drink.Id,
+			Kind:           "drink",
+			Name:           drink.Name,
+			Description:    drink.Description,
+			Image:          drink.Image,
+			Price:          drink.Price,
+			ModifierGroups: drink.ModifierGroups,
+			Variants:       drink.Variants,
+		})
+	}
+	for _, combo := range combos {
+		if !offeredSlots(&combo, offered) {
+			continue
+		}
+		add(combo.Category, models.MenuItem{
+			Id:          combo.Id,
+			Kind:        "combo",
+			Name:        combo.Name,
+			Description: combo.Description,
+			Image:       combo.Image,
+			Price:       combo.Price,
+			Slots:       combo.Slots,
+		})
+	}
+
+	menu := models.Menu{
+		Restaurant:   receipt_services.RestaurantFromEnv().Name,
+		TaxInclusive: settings.TaxInclusive,
+		Categories:   []models.MenuCategory{},
+	}
+	for name, items := range byCategory {
+		sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+		menu.Categories = append(menu.Categories, models.MenuCategory{Name: name, Items: items})
+	}
+	sort.Slice(menu.Categories, func(i, j int) bool { return menu.Categories[i].Name < menu.Categories[j].Name })
+	return &menu, nil
+}
+
+// offeredSlots narrows the choices of a combo's slots to the offered items and
+// reports whether every slot still has one.
+func offeredSlots(combo *models.Combo, offered map[primitive.ObjectID]bool) bool {
+	slots := make([]models.ComboSlot, len(combo.Slots))
+	for i, slot := range combo.Slots {
+		slots[i] = models.ComboSlot{Name: slot.Name}
+		for _, id := range slot.FoodIds {
+			if offered[id] {
+				slots[i].FoodIds = append(slots[i].FoodIds, id)
+			}
+		}
+		for _, id := range slot.DrinkIds {
+			if offered[id] {
+				slots[i].DrinkIds = append(slots[i].DrinkIds, id)
+			}
+		}
+		if len(slots[i].FoodIds) == 0 && len(slots[i].DrinkIds) == 0 {
+			return false
+		}
+	}
+	combo.Slots = slots
+	return true
+}
+
+// TableQRCode returns the PNG code guests at a table scan to open the menu
+// and order. Printing a new code rotates the table's guest token.
+func (usecase *UsecaseImplemented) TableQRCode(id string) ([]byte, error) {
+	token, err := usecase.IssueGuestToken(id)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.QR.GenerateQRCode(token.Table, token.Token)
+}
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// menuOffer decides which menu items are on offer at one moment.
+type menuOffer struct {
+	schedules []models.MenuSchedule
+	at        time.Time
+}
+
+// menuOfferAt loads the active menu schedules and places at in the
+// restaurant's timezone.
+func (usecase *UsecaseImplemented) menuOfferAt(at time.Time) (*menuOffer, error) {
+	schedules, err := usecase.Repo.GetMenuSchedules(true)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	return &menuOffer{schedules: schedules, at: at.In(settings.Location())}, nil
+}
+
+// scheduled reports whether the schedules offer an item: always if none of
+// them covers it, otherwise while one that does is in its window.
+func (offer *menuOffer) scheduled(category string, id primitive.ObjectID, itemIds func(models.MenuSchedule) []primitive.ObjectID) bool {
+	covered := false
+	for _, schedule := range offer.schedules {
+		if !containsString(schedule.Categories, category) && !containsId(itemIds(schedule), id) {
+			continue
+		}
+		if inWindow(schedule.Weekdays, schedule.Start, schedule.End, offer.at) {
+			return true
+		}
+		covered = true
+	}
+	return !covered
+}
+
+func (offer *menuOffer) food(food *models.Food) bool {
+	return !food.SoldOut && offer.scheduled(food.Category, food.Id, func(schedule models.MenuSchedule) []primitive.ObjectID {
+		return schedule.FoodIds
+	})
+}
+
+func (offer *menuOffer) drink(drink *models.Drink) bool {
+	return !drink.SoldOut && offer.scheduled(drink.Category, drink.Id, func(schedule models.MenuSchedule) []primitive.ObjectID {
+		return schedule.DrinkIds
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOffered rejects order lines of items that are sold out or off their
+// menu schedule at time at. An item the order already had before a change is
+// let through as long as its quantity does not grow, so an order can still be
+// edited after one of its items runs out.
+func (usecase *UsecaseImplemented) checkOffered(order, before *models.Order, at time.Time) error {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	had := make(map[primitive.ObjectID]float64)
+	if before != nil {
+		for _, line := range before.Foods {
+			had[line.FoodId] += line.Quantity
+		}
+		for _, line := range before.Drinks {
+			had[line.DrinkId] += line.Quantity
+		}
+	}
+	wants := make(map[primitive.ObjectID]float64)
+	for _, line := range order.Foods {
+		wants[line.FoodId] += line.Quantity
+	}
+	for _, line := range order.Drinks {
+		wants[line.DrinkId] += line.Quantity
+	}
+	offer, err := usecase.menuOfferAt(at)
+	if err != nil {
+		return err
+	}
+	checked := make(map[primitive.ObjectID]bool)
+	for _, line := range order.Foods {
+		if wants[line.FoodId] <= had[line.FoodId] || checked[line.FoodId] {
+			continue
+		}
+		checked[line.FoodId] = true
+		food, err := usecase.Repo.GetFoodById(line.FoodId.Hex())
+		if err != nil {
+			return fmt.Errorf("%w: food %s", ErrItemNotFound, line.FoodId.Hex())
+		}
+		if !offer.food(food) {
+			return fmt.Errorf("%w: %s", ErrItemUnavailable, food.Name)
+		}
+	}
+	for _, line := range order.Drinks {
+		if wants[line.DrinkId] <= had[line.DrinkId] || checked[line.DrinkId] {
+			continue
+		}
+		checked[line.DrinkId] = true
+		drink, err := usecase.Repo.GetDrinkById(line.DrinkId.Hex())
+		if err != nil {
+			return fmt.Errorf("%w: drink %s", ErrItemNotFound, line.DrinkId.Hex())
+		}
+		if !offer.drink(drink) {
+			return fmt.Errorf("%w: %s", ErrItemUnavailable, drink.Name)
+		}
+	}
+	return nil
+}
+
+// SetFoodSoldOut marks a food sold out or back on, for the kitchen to 86 an
+// item quickly.
+func (usecase *UsecaseImplemented) SetFoodSoldOut(id string, soldOut bool) (*models.Food, error) {
+	if err := usecase.Repo.SetFoodSoldOut(id, soldOut); err != nil {
+		return nil, fmt.Errorf("%w: food %s", ErrItemNotFound, id)
+	}
+	return usecase.Repo.GetFoodById(id)
+}
+
+// SetDrinkSoldOut marks a drink sold out or back on.
+func (usecase *UsecaseImplemented) SetDrinkSoldOut(id string, soldOut bool) (*models.Drink, error) {
+	if err := usecase.Repo.SetDrinkSoldOut(id, soldOut); err != nil {
+		return nil, fmt.Errorf("%w: drink %s", ErrItemNotFound, id)
+	}
+	return usecase.Repo.GetDrinkById(id)
+}
+
+func validateMenuSchedule(schedule *models.MenuSchedule) error {
+	if schedule.Name == "" {
+		return fmt.Errorf("%w: a name is required", ErrInvalidMenuSchedule)
+	}
+	if len(schedule.Categories) == 0 && len(schedule.FoodIds) == 0 && len(schedule.DrinkIds) == 0 {
+		return fmt.Errorf("%w: give the categories or items it covers", ErrInvalidMenuSchedule)
+	}
+	if err := validateWindow(schedule.Weekdays, schedule.Start, schedule.End); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMenuSchedule, err)
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) CreateMenuSchedule(schedule *models.MenuSchedule) error {
+	if err := validateMenuSchedule(schedule); err != nil {
+		return err
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+	return usecase.Repo.CreateMenuSchedule(schedule)
+}
+
+func (usecase *UsecaseImplemented) UpdateMenuSchedule(schedule *models.MenuSchedule) error {
+	if err := validateMenuSchedule(schedule); err != nil {
+		return err
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+	if err := usecase.Repo.UpdateMenuSchedule(schedule); err != nil {
+		return ErrMenuScheduleNotFound
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) DeleteMenuSchedule(id string) error {
+	if err := usecase.Repo.DeleteMenuSchedule(id); err != nil {
+		return ErrMenuScheduleNotFound
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) GetMenuSchedules() ([]models.MenuSchedule, error) {
+	return usecase.Repo.GetMenuSchedules(false)
+}
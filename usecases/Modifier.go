@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+const maxNoteLength = 200
+
+// ValidateModifierGroups checks the modifier groups and variants of a menu item.
+func ValidateModifierGroups(groups []models.ModifierGroup, variants []models.Variant) error {
+	seenGroups := make(map[string]bool)
+	for _, group := range groups {
+		if group.Name == "" || seenGroups[group.Name] {
+			return fmt.Errorf("%w: modifier group names must be set and unique", ErrInvalidMenuItem)
+		}
+		seenGroups[group.Name] = true
+		if len(group.Options) == 0 {
+			return fmt.Errorf("%w: modifier group %q has no options", ErrInvalidMenuItem, group.Name)
+		}
+		if group.MinChoices < 0 || group.MaxChoices < 0 ||
+			(group.MaxChoices > 0 && group.MinChoices > group.MaxChoices) ||
+			(group.MaxChoices > len(group.Options)) {
+			return fmt.Errorf("%w: modifier group %q has invalid choice limits", ErrInvalidMenuItem, group.Name)
+		}
+		seenOptions := make(map[string]bool)
+		for _, option := range group.Options {
+			if option.Name == "" || seenOptions[option.Name] {
+				return fmt.Errorf("%w: options of %q must be named and unique", ErrInvalidMenuItem, group.Name)
+			}
+			seenOptions[option.Name] = true
+		}
+	}
+	seenVariants := make(map[string]bool)
+	for _, variant := range variants {
+		if variant.Name == "" || seenVariants[variant.Name] || variant.Price < 0 {
+			return fmt.Errorf("%w: variants must be named, unique and not negative", ErrInvalidMenuItem)
+		}
+		seenVariants[variant.Name] = true
+	}
+	return nil
+}
+
+// priceModifiers validates the modifiers chosen on a line against the item's
+// groups, copies each option's price delta onto the line and returns the total
+// the modifiers add to one unit.
+func priceModifiers(item string, groups []models.ModifierGroup, chosen []models.ChosenModifier) (float64, error) {
+	picked := make(map[string]map[string]bool)
+	delta := 0.0
+	for i := range chosen {
+		var group *models.ModifierGroup
+		for g := range groups {
+			if groups[g].Name == chosen[i].Group {
+				group = &groups[g]
+				break
+			}
+		}
+		if group == nil {
+			return 0, fmt.Errorf("%w: %s has no modifier group %q", ErrInvalidOrder, item, chosen[i].Group)
+		}
+		var option *models.ModifierOption
+		for o := range group.Options {
+			if group.Options[o].Name == chosen[i].Option {
+				option = &group.Options[o]
+				break
+			}
+		}
+		if option == nil {
+			return 0, fmt.Errorf("%w: %q is not an option of %s %q", ErrInvalidOrder, chosen[i].Option, item, group.Name)
+		}
+		if picked[group.Name] == nil {
+			picked[group.Name] = make(map[string]bool)
+		}
+		if picked[group.Name][option.Name] {
+			return 0, fmt.Errorf("%w: %q chosen twice on %s", ErrInvalidOrder, option.Name, item)
+		}
+		picked[group.Name][option.Name] = true
+		chosen[i].PriceDelta = option.PriceDelta
+		delta += option.PriceDelta
+	}
+	for _, group := range groups {
+		count := len(picked[group.Name])
+		min := group.MinChoices
+		if group.Required && min < 1 {
+			min = 1
+		}
+		if count < min {
+			return 0, fmt.Errorf("%w: %s needs at least %d choice(s) from %q", ErrInvalidOrder, item, min, group.Name)
+		}
+		if group.MaxChoices > 0 && count > group.MaxChoices {
+			return 0, fmt.Errorf("%w: %s allows at most %d choice(s) from %q", ErrInvalidOrder, item, group.MaxChoices, group.Name)
+		}
+	}
+	return delta, nil
+}
+
+// variantPrice returns the base price of a drink in the chosen variant. Drinks
+// with variants must name one; drinks without must not.
+func variantPrice(drink *models.Drink, variant string) (float64, error) {
+	if len(drink.Variants) == 0 {
+		if variant != "" {
+			return 0, fmt.Errorf("%w: %s has no variants", ErrInvalidOrder, drink.Name)
+		}
+		return drink.Price, nil
+	}
+	for _, v := range drink.Variants {
+		if v.Name == variant {
+			return v.Price, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s needs one of its variants, got %q", ErrInvalidOrder, drink.Name, variant)
+}
@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// FoodTotalPrice looks up every food line in the catalog, checks its modifiers
+// and snapshots the catalog name, category and unit price onto the line.
+// Lines marked kept hold their stored snapshot and only get a new total.
+func (usecase *UsecaseImplemented) FoodTotalPrice(items []models.FoodOrder, kept []bool, wg *sync.WaitGroup, errChan chan error) {
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if items[i].Quantity <= 0 {
+				errChan <- fmt.Errorf("%w: quantity must be positive for food %s", ErrInvalidOrder, items[i].FoodId.Hex())
+				return
+			}
+			if kept[i] {
+				items[i].TotalPrice = items[i].Price * items[i].Quantity
+				return
+			}
+			food, err := usecase.Repo.GetFoodById(items[i].FoodId.Hex())
+			if err != nil {
+				errChan <- fmt.Errorf("%w: food %s", ErrItemNotFound, items[i].FoodId.Hex())
+				return
+			}
+			if len(items[i].Note) > maxNoteLength {
+				errChan <- fmt.Errorf("%w: note on %s is longer than %d characters", ErrInvalidOrder, food.Name, maxNoteLength)
+				return
+			}
+			delta, err := priceModifiers(food.Name, food.ModifierGroups, items[i].Modifiers)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			items[i].FoodName = food.Name
+			items[i].Category = food.Category
+			items[i].Price = food.Price + delta
+			items[i].TotalPrice = items[i].Price * items[i].Quantity
+		}(i)
+	}
+}
+
+// DrinkTotalPrice does the same as FoodTotalPrice for drink lines, pricing
+// from the chosen variant when the drink has sizes.
+func (usecase *UsecaseImplemented) DrinkTotalPrice(items []models.DrinkOrder, kept []bool, wg *sync.WaitGroup, errChan chan error) {
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if items[i].Quantity <= 0 {
+				errChan <- fmt.Errorf("%w: quantity must be positive for drink %s", ErrInvalidOrder, items[i].DrinkId.Hex())
+				return
+			}
+			if kept[i] {
+				items[i].TotalPrice = items[i].Price * items[i].Quantity
+				return
+			}
+			drink, err := usecase.Repo.GetDrinkById(items[i].DrinkId.Hex())
+			if err != nil {
+				errChan <- fmt.Errorf("%w: drink %s", ErrItemNotFound, items[i].DrinkId.Hex())
+				return
+			}
+			if len(items[i].Note) > maxNoteLength {
+				errChan <- fmt.Errorf("%w: note on %s is longer than %d characters", ErrInvalidOrder, drink.Name, maxNoteLength)
+				return
+			}
+			base, err := variantPrice(drink, items[i].Variant)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			delta, err := priceModifiers(drink.Name, drink.ModifierGroups, items[i].Modifiers)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			items[i].DrinkName = drink.Name
+			items[i].Category = drink.Category
+			items[i].Price = base + delta
+			items[i].TotalPrice = items[i].Price * items[i].Quantity
+		}(i)
+	}
+}
+
+// PriceOrder prices the lines of the order from the menu catalog and works
+// out the order totals. Combos are expanded into their lines first. Lines and
+// combos unchanged since before keep the prices stored on them; the others
+// are priced as added at at. Client supplied names and prices are overwritten.
+func (usecase *UsecaseImplemented) PriceOrder(order, before *models.Order, at time.Time) error {
+	if err := usecase.expandCombos(order, before); err != nil {
+		return err
+	}
+	keptFoods, keptDrinks := stampLines(order, before, at)
+	var wg sync.WaitGroup
+	errChan := make(chan error, len(order.Foods)+len(order.Drinks))
+
+	usecase.FoodTotalPrice(order.Foods, keptFoods, &wg, errChan)
+	usecase.DrinkTotalPrice(order.Drinks, keptDrinks, &wg, errChan)
+
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		if err != nil {
+			return err
+		}
+	}
+	shareComboPrice(order)
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return err
+	}
+	rules, err := usecase.Repo.GetPricingRules(true)
+	if err != nil {
+		return err
+	}
+	applyPricingRules(order, rules, settings.Location(), keptFoods, keptDrinks)
+	return ComputeTotals(order, settings)
+}
+
+// TransitionOrder moves the order to status if the lifecycle allows it and
+// records who made the move and when.
+func (usecase *UsecaseImplemented) TransitionOrder(id, status string, employeeId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if !models.CanTransition(order.Status, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, status)
+	}
+	if status == models.OrderPaid && order.Unsettled() {
+		return nil, fmt.Errorf("%w: split check has unsettled parts", ErrInvalidTransition)
+	}
+	if status == models.OrderPaid && len(order.Splits) == 0 {
+		payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+		if err != nil {
+			return nil, err
+		}
+		due, err := amountDue(order, 0, payments)
+		if err != nil {
+			return nil, err
+		}
+		if due > 0 {
+			return nil, fmt.Errorf("%w: %.2f is still to be paid", ErrInvalidTransition, due)
+		}
+	}
+	change := models.StatusChange{
+		From:       order.Status,
+		To:         status,
+		EmployeeId: employeeId,
+		At:         time.Now().UTC(),
+	}
+	if err := usecase.Repo.UpdateOrderStatus(id, change); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+	before := *order
+	order.Status = status
+	order.StatusHistory = append(order.StatusHistory, change)
+	order.Version++
+	usecase.recordChange(&before, order, employeeId)
+	if order.IsClosed() {
+		usecase.releaseTable(order.TableNumber)
+	}
+	usecase.publishOrderEvent(models.EventStatusChanged, *order)
+	return order, nil
+}
+
+// publishOrderEvent stores the event so displays can resume from it and pushes
+// it to every connected display. A failure here never fails the order itself.
+func (usecase *UsecaseImplemented) publishOrderEvent(eventType string, order models.Order) {
+	event := models.OrderEvent{
+		Type:    eventType,
+		OrderId: order.Id,
+		Order:   order,
+		At:      time.Now().UTC(),
+	}
+	if err := usecase.Repo.CreateOrderEvent(&event); err != nil {
+		log.Printf("failed to record %s event for order %s: %v", eventType, order.Id.Hex(), err)
+		return
+	}
+	usecase.Events.Publish(event)
+}
+
+// SubscribeOrderEvents returns the stored events after seq together with a
+// live channel. The live channel is opened first so nothing falls between the
+// two; callers skip live events they already got from the backlog.
+func (usecase *UsecaseImplemented) SubscribeOrderEvents(seq int64) ([]models.OrderEvent, chan models.OrderEvent, error) {
+	ch := usecase.Events.Subscribe()
+	backlog, err := usecase.Repo.GetOrderEventsAfter(seq)
+	if err != nil {
+		usecase.Events.Unsubscribe(ch)
+		return nil, nil, err
+	}
+	return backlog, ch, nil
+}
+
+func (usecase *UsecaseImplemented) UnsubscribeOrderEvents(ch chan models.OrderEvent) {
+	usecase.Events.Unsubscribe(ch)
+}
+
+func (usecase *UsecaseImplemented) GetOrderEventsAfter(seq int64) ([]models.OrderEvent, error) {
+	return usecase.Repo.GetOrderEventsAfter(seq)
+}
+
+// validateOrderType checks that an order carries what its type needs: a table
+// for dine-in, a pickup time for takeaway and the customer's details for
+// delivery. Orders without a type are dine-in.
+func (usecase *UsecaseImplemented) validateOrderType(order *models.Order) error {
+	if order.Type == "" {
+		order.Type = models.OrderDineIn
+	}
+	if !models.ValidOrderType(order.Type) {
+		return fmt.Errorf("%w: unknown order type %q", ErrInvalidOrder, order.Type)
+	}
+	if order.Type != models.OrderDelivery && order.Delivery != nil {
+		return fmt.Errorf("%w: only delivery orders take delivery details", ErrInvalidOrder)
+	}
+	if order.Type != models.OrderTakeaway && !order.PickupAt.IsZero() {
+		return fmt.Errorf("%w: only takeaway orders take a pickup time", ErrInvalidOrder)
+	}
+	if order.Type != models.OrderDineIn && order.TableNumber != 0 {
+		return fmt.Errorf("%w: %s orders are not served at a table", ErrInvalidOrder, order.Type)
+	}
+
+	switch order.Type {
+	case models.OrderDineIn:
+		if _, err := usecase.Repo.GetTableByNumber(order.TableNumber); err != nil {
+			return ErrTableNotFound
+		}
+	case models.OrderTakeaway:
+		if order.PickupAt.IsZero() {
+			return fmt.Errorf("%w: takeaway orders need a pickup time", ErrInvalidOrder)
+		}
+	case models.OrderDelivery:
+		delivery := order.Delivery
+		if delivery == nil || delivery.CustomerName == "" || delivery.Phone == "" || delivery.Address == "" {
+			return fmt.Errorf("%w: delivery orders need the customer's name, phone and address", ErrInvalidOrder)
+		}
+		if delivery.PromisedAt.IsZero() {
+			return fmt.Errorf("%w: delivery orders need a promised time", ErrInvalidOrder)
+		}
+		if delivery.Fee < 0 {
+			return fmt.Errorf("%w: delivery fee cannot be negative", ErrInvalidOrder)
+		}
+		// drivers are assigned through AssignDriver
+		delivery.DriverId = primitive.NilObjectID
+		delivery.AssignedAt = time.Time{}
+	}
+	return nil
+}
+
+// AssignDriver hands an open delivery order to a driver.
+func (usecase *UsecaseImplemented) AssignDriver(id string, driverId, employeeId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.OrderType() != models.OrderDelivery || order.Delivery == nil {
+		return nil, fmt.Errorf("%w: only delivery orders have a driver", ErrInvalidOrder)
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	if _, err := usecase.Repo.GetEmployeeById(driverId.Hex()); err != nil {
+		return nil, fmt.Errorf("%w: driver %s", ErrInvalidOrder, driverId.Hex())
+	}
+	before := *order
+	delivery := *order.Delivery
+	delivery.DriverId = driverId
+	delivery.AssignedAt = time.Now().UTC()
+	order.Delivery = &delivery
+	if err := usecase.Repo.UpdateOrder(order); err != nil {
+		return nil, orderWriteError(err)
+	}
+	usecase.recordChange(&before, order, employeeId)
+	usecase.publishOrderEvent(models.EventOrderUpdated, *order)
+	return order, nil
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+	"github.com/yesetoda/kushena/repositories"
+)
+
+// orderWriteError turns a repository version conflict into ErrVersionConflict
+// and anything else that is not found into ErrOrderNotFound.
+func orderWriteError(err error) error {
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return ErrVersionConflict
+	}
+	if err != nil && err.Error() == "order not found" {
+		return ErrOrderNotFound
+	}
+	return err
+}
+
+// diffOrder lists the tracked fields that differ between before and after,
+// sorted by field name so the history reads the same every time.
+func diffOrder(before, after *models.Order) []models.FieldChange {
+	from := before.TrackedFields()
+	to := after.TrackedFields()
+	var changes []models.FieldChange
+	for field, value := range to {
+		if !reflect.DeepEqual(from[field], value) {
+			changes = append(changes, models.FieldChange{Field: field, From: from[field], To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// recordChange appends what changed between before and after to the order's
+// history. Like events, a failure here never fails the order itself.
+func (usecase *UsecaseImplemented) recordChange(before, after *models.Order, employeeId primitive.ObjectID) {
+	changes := diffOrder(before, after)
+	if len(changes) == 0 {
+		return
+	}
+	change := models.OrderChange{
+		OrderId:    after.Id,
+		Version:    after.Version,
+		EmployeeId: employeeId,
+		At:         time.Now().UTC(),
+		Changes:    changes,
+	}
+	if err := usecase.Repo.CreateOrderChange(&change); err != nil {
+		log.Printf("failed to record history of order %s: %v", after.Id.Hex(), err)
+	}
+}
+
+func (usecase *UsecaseImplemented) GetOrderHistory(id string) ([]models.OrderChange, error) {
+	orderId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if _, err := usecase.Repo.GetOrderById(id); err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return usecase.Repo.GetOrderHistory(orderId)
+}
@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// amountDue returns what is still owed on an order, or on one part of its
+// split check, after the payments already taken.
+func amountDue(order *models.Order, part int, payments []models.Payment) (float64, error) {
+	due := order.TotalPrice
+	if part != 0 {
+		due = -1
+		for _, p := range order.Splits {
+			if p.Number == part {
+				due = p.Amount
+				break
+			}
+		}
+		if due < 0 {
+			return 0, fmt.Errorf("%w: order has no part %d", ErrInvalidPayment, part)
+		}
+	} else if len(order.Splits) > 0 {
+		return 0, fmt.Errorf("%w: the check is split, pay one part at a time", ErrInvalidPayment)
+	}
+	for _, payment := range payments {
+		if payment.Part == part {
+			due -= payment.Net()
+		}
+	}
+	return roundMoney(due), nil
+}
+
+// RecordPayment takes a payment against an order. Amount defaults to the
+// balance due; cash may be over-tendered and the change is worked out here.
+// A payment that clears a split part settles it, and one that clears a served
+// order marks the order paid. Payments on an order with a customer earn
+// loyalty points, and loyalty_points payments spend them. A voucher payment
+// takes what it can from the voucher's balance.
+func (usecase *UsecaseImplemented) RecordPayment(orderId string, request models.PaymentRequest, employeeId primitive.ObjectID) (*models.Payment, error) {
+	if !models.ValidPaymentMethod(request.Method) {
+		return nil, fmt.Errorf("%w: unknown method %q", ErrInvalidPayment, request.Method)
+	}
+	order, err := usecase.Repo.GetOrderById(orderId)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+	if err != nil {
+		return nil, err
+	}
+	due, err := amountDue(order, request.Part, payments)
+	if err != nil {
+		return nil, err
+	}
+	if due <= 0 {
+		return nil, fmt.Errorf("%w: nothing left to pay", ErrInvalidPayment)
+	}
+
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Tip < 0 || (request.Tip > 0 && !tipMethod(request.Method)) {
+		return nil, fmt.Errorf("%w: tips are positive and taken in cash, card or mobile money", ErrInvalidPayment)
+	}
+
+	amount := request.Amount
+	if amount == 0 {
+		amount = due
+	}
+	if amount < 0 || amount > due {
+		return nil, fmt.Errorf("%w: amount must be between 0 and %.2f", ErrInvalidPayment, due)
+	}
+	payment := models.Payment{
+		OrderId:    order.Id,
+		Part:       request.Part,
+		Method:     request.Method,
+		Amount:     roundMoney(amount),
+		Tendered:   roundMoney(amount + request.Tip),
+		Tip:        roundMoney(request.Tip),
+		EmployeeId: employeeId,
+		CreatedAt:  time.Now().UTC(),
+	}
+	// bumping the version read above makes a concurrent payment on the same
+	// order fail instead of both passing the balance check
+	if err := usecase.Repo.PatchOrder(order.Id, order.Version, nil, nil); err != nil {
+		return nil, orderWriteError(err)
+	}
+	var voucher *models.Voucher
+	switch request.Method {
+	case models.PaymentPoints:
+		points, redeemed, err := usecase.redeemPoints(order, request, due, settings.Loyalty)
+		if err != nil {
+			return nil, err
+		}
+		payment.Amount, payment.Tendered, payment.Points = redeemed, redeemed, points
+	case models.PaymentVoucher:
+		var spent float64
+		if voucher, spent, err = usecase.spendVoucher(request, payment.Amount, payment.CreatedAt); err != nil {
+			return nil, err
+		}
+		payment.Amount, payment.Tendered, payment.VoucherId = spent, spent, voucher.Id
+	}
+	if request.Method != models.PaymentPoints && !order.CustomerId.IsZero() {
+		payment.PointsEarned = int(math.Floor(payment.Amount*settings.Loyalty.EarnRate + 1e-9))
+	}
+	if request.Method == models.PaymentCash && request.Tendered != 0 {
+		owed := amount + request.Tip
+		if request.Tendered < owed {
+			return nil, fmt.Errorf("%w: tendered %.2f is less than %.2f", ErrInvalidPayment, request.Tendered, owed)
+		}
+		payment.Tendered = roundMoney(request.Tendered)
+		payment.Change = roundMoney(request.Tendered - owed)
+	}
+	if err := usecase.Repo.CreatePayment(&payment); err != nil {
+		if payment.Points > 0 {
+			if err := usecase.Repo.RecordCustomerSpend(order.CustomerId, 0, payment.Points, false, payment.CreatedAt); err != nil {
+				log.Printf("failed to give back %d points to customer %s: %v", payment.Points, order.CustomerId.Hex(), err)
+			}
+		}
+		if voucher != nil {
+			usecase.creditVoucher(&payment, payment.Amount, "", employeeId, payment.CreatedAt)
+		}
+		return nil, err
+	}
+	usecase.settleLoyalty(order, &payment, len(payments) == 0)
+	if payment.Tip > 0 {
+		tip := models.Tip{
+			OrderId:    order.Id,
+			PaymentId:  payment.Id,
+			Amount:     payment.Tip,
+			Method:     payment.Method,
+			EmployeeId: employeeId,
+			At:         payment.CreatedAt,
+		}
+		if err := usecase.Repo.CreateTip(&tip); err != nil {
+			log.Printf("failed to record tip of payment %s: %v", payment.Id.Hex(), err)
+		}
+	}
+	if voucher != nil {
+		usecase.writeVoucherEntry(models.VoucherEntry{
+			VoucherId:  voucher.Id,
+			Kind:       models.VoucherRedeemed,
+			Amount:     -payment.Amount,
+			Balance:    voucher.Balance,
+			OrderId:    order.Id,
+			PaymentId:  payment.Id,
+			EmployeeId: employeeId,
+			At:         payment.CreatedAt,
+		})
+	}
+
+	if roundMoney(due-payment.Amount) > 0 {
+		return &payment, nil
+	}
+	if request.Part != 0 {
+		if _, err := usecase.SettleBillPart(orderId, request.Part, employeeId); err != nil {
+			return &payment, err
+		}
+	} else if order.Status == models.OrderServed {
+		if _, err := usecase.TransitionOrder(orderId, models.OrderPaid, employeeId); err != nil {
+			return &payment, err
+		}
+	}
+	return &payment, nil
+}
+
+func (usecase *UsecaseImplemented) GetOrderPayments(orderId string) ([]models.Payment, error) {
+	order, err := usecase.Repo.GetOrderById(orderId)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return usecase.Repo.GetPaymentsByOrder(order.Id)
+}
+
+// RefundPayment refunds all or part of what is left on a payment. Amount
+// defaults to the whole remaining balance.
+func (usecase *UsecaseImplemented) RefundPayment(id string, request models.RefundRequest, employeeId primitive.ObjectID) (*models.Payment, error) {
+	payment, err := usecase.Repo.GetPaymentById(id)
+	if err != nil {
+		return nil, ErrPaymentNotFound
+	}
+	remaining := roundMoney(payment.Net())
+	amount := request.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount <= 0 || amount > remaining {
+		return nil, fmt.Errorf("%w: refund must be between 0 and %.2f", ErrInvalidPayment, remaining)
+	}
+	refund := models.Refund{
+		Amount:     roundMoney(amount),
+		Reason:     request.Reason,
+		EmployeeId: employeeId,
+		At:         time.Now().UTC(),
+	}
+	if err := usecase.Repo.AddRefund(payment.Id, payment.Refunded, refund); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayment, err)
+	}
+	usecase.reverseLoyalty(payment, refund)
+	if payment.Method == models.PaymentVoucher {
+		// voucher money goes back on the voucher, not out of the till
+		usecase.creditVoucher(payment, refund.Amount, models.VoucherRefunded, employeeId, refund.At)
+	}
+	payment.Refunded += refund.Amount
+	payment.Refunds = append(payment.Refunds, refund)
+	return payment, nil
+}
+
+// businessDay returns midnight in the restaurant's location of the calendar
+// date day carries, or of today there when day is zero.
+func businessDay(day time.Time, location *time.Location) time.Time {
+	if day.IsZero() {
+		day = time.Now().In(location)
+	}
+	year, month, date := day.Date()
+	return time.Date(year, month, date, 0, 0, 0, 0, location)
+}
+
+// ReconcileCash closes a cashier's day in the restaurant's timezone: the
+// opening float plus the cash and cash tips they took, less the cash they
+// refunded, is what should be in the drawer.
+func (usecase *UsecaseImplemented) ReconcileCash(employeeId primitive.ObjectID, request models.ReconciliationRequest) (*models.CashReconciliation, error) {
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	from := businessDay(now.In(settings.Location()), settings.Location())
+	payments, err := usecase.Repo.GetEmployeePayments(employeeId, from, now)
+	if err != nil {
+		return nil, err
+	}
+	reconciliation := models.CashReconciliation{
+		EmployeeId: employeeId,
+		From:       from.UTC(),
+		To:         now.UTC(),
+		Float:      request.Float,
+		Counted:    request.Counted,
+		CreatedAt:  now.UTC(),
+	}
+	for _, payment := range payments {
+		if payment.Method != models.PaymentCash {
+			continue
+		}
+		if payment.EmployeeId == employeeId && !payment.CreatedAt.Before(from) {
+			reconciliation.CashTaken += payment.Amount
+			reconciliation.CashTips += payment.Tip
+		}
+		for _, refund := range payment.Refunds {
+			if refund.EmployeeId == employeeId && !refund.At.Before(from) {
+				reconciliation.CashRefund += refund.Amount
+			}
+		}
+	}
+	reconciliation.CashTaken = roundMoney(reconciliation.CashTaken)
+	reconciliation.CashRefund = roundMoney(reconciliation.CashRefund)
+	reconciliation.CashTips = roundMoney(reconciliation.CashTips)
+	reconciliation.Expected = roundMoney(request.Float + reconciliation.CashTaken + reconciliation.CashTips - reconciliation.CashRefund)
+	reconciliation.Difference = roundMoney(request.Counted - reconciliation.Expected)
+	if err := usecase.Repo.CreateReconciliation(&reconciliation); err != nil {
+		return nil, err
+	}
+	return &reconciliation, nil
+}
+
+// GetReconciliations lists the reconciliations of a day in the restaurant's
+// timezone, today when day is zero.
+func (usecase *UsecaseImplemented) GetReconciliations(day time.Time) ([]models.CashReconciliation, error) {
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	from := businessDay(day, settings.Location())
+	return usecase.Repo.GetReconciliations(from, from.AddDate(0, 0, 1))
+}
@@ -0,0 +1,336 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// validatePricingRule checks a rule's scope, days, window and price change,
+// and lower-cases its weekdays.
+func validatePricingRule(rule *models.PricingRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("%w: a name is required", ErrInvalidPricingRule)
+	}
+	if rule.Category == "" && len(rule.FoodIds) == 0 && len(rule.DrinkIds) == 0 {
+		return fmt.Errorf("%w: give a category or the items it covers", ErrInvalidPricingRule)
+	}
+	if err := validateWindow(rule.Weekdays, rule.Start, rule.End); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPricingRule, err)
+	}
+	switch rule.Type {
+	case models.PricingPercent:
+		if rule.Value <= 0 || rule.Value > 100 {
+			return fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidPricingRule)
+		}
+	case models.PricingFixedPrice:
+		if rule.Value < 0 {
+			return fmt.Errorf("%w: price cannot be negative", ErrInvalidPricingRule)
+		}
+		rule.Value = roundMoney(rule.Value)
+	default:
+		return fmt.Errorf("%w: type must be percent or fixed_price", ErrInvalidPricingRule)
+	}
+	return nil
+}
+
+// validateWindow checks the weekdays and "HH:MM" bounds of a weekly time
+// window, lower-casing the weekdays.
+func validateWindow(weekdays []string, start, end string) error {
+	for i, day := range weekdays {
+		weekdays[i] = strings.ToLower(day)
+		if weekdayIndex(weekdays[i]) < 0 {
+			return fmt.Errorf("unknown weekday %q, use %s", day, strings.Join(models.Weekdays, ", "))
+		}
+	}
+	if _, err := parseClock(start); err != nil {
+		return errors.New("start must be HH:MM")
+	}
+	if _, err := parseClock(end); err != nil {
+		return errors.New("end must be HH:MM")
+	}
+	return nil
+}
+
+func weekdayIndex(day string) int {
+	for i, name := range models.Weekdays {
+		if name == day {
+			return i
+		}
+	}
+	return -1
+}
+
+// inWindow reports whether a weekly window covers local time at. For a window
+// past midnight the early hours count towards the day before; no weekdays
+// means every day.
+func inWindow(weekdays []string, startClock, endClock string, at time.Time) bool {
+	start, _ := parseClock(startClock)
+	end, _ := parseClock(endClock)
+	clock := time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+	day := at.Weekday()
+	switch {
+	case start < end:
+		if clock < start || clock >= end {
+			return false
+		}
+	case clock >= start:
+	case clock < end:
+		day = (day + 6) % 7
+	default:
+		return false
+	}
+	if len(weekdays) == 0 {
+		return true
+	}
+	for _, name := range weekdays {
+		if weekdayIndex(name) == int(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleCovers reports whether a rule applies to an item of a category.
+func ruleCovers(rule models.PricingRule, itemIds []primitive.ObjectID, itemId primitive.ObjectID, category string) bool {
+	if rule.Category != "" && rule.Category == category {
+		return true
+	}
+	for _, id := range itemIds {
+		if id == itemId {
+			return true
+		}
+	}
+	return false
+}
+
+// rulePrice is the unit price a rule gives a line priced at price, of which
+// modifiers make up extras.
+func rulePrice(rule models.PricingRule, price, extras float64) float64 {
+	if rule.Type == models.PricingFixedPrice {
+		return roundMoney(rule.Value + extras)
+	}
+	return roundMoney(price * (1 - rule.Value/100))
+}
+
+func modifierExtras(modifiers []models.ChosenModifier) float64 {
+	extras := 0.0
+	for _, modifier := range modifiers {
+		extras += modifier.PriceDelta
+	}
+	return extras
+}
+
+// bestRule picks, among the rules in effect for a line, the one giving the
+// lowest price; the older rule wins a tie. It returns nil when none applies.
+func bestRule(rules []models.PricingRule, covers func(models.PricingRule) bool, price, extras float64) (*models.PricingRule, float64) {
+	var best *models.PricingRule
+	bestPrice := price
+	for i := range rules {
+		if !covers(rules[i]) {
+			continue
+		}
+		if p := rulePrice(rules[i], price, extras); best == nil || p < bestPrice {
+			best, bestPrice = &rules[i], p
+		}
+	}
+	return best, bestPrice
+}
+
+// applyPricingRules reprices the lines of an order that a rule in effect when
+// the line was added covers, in the restaurant's timezone, and records the
+// rule on the line. Lines come in at their menu price; unchanged lines keep
+// the price they have and combo lines their share of the bundle price.
+func applyPricingRules(order *models.Order, rules []models.PricingRule, location *time.Location, keptFoods, keptDrinks []bool) {
+	placed := order.CreatedAt
+	if placed.IsZero() {
+		placed = time.Now()
+	}
+	liveAt := func(added time.Time) []models.PricingRule {
+		if added.IsZero() {
+			added = placed
+		}
+		at := added.In(location)
+		var live []models.PricingRule
+		for _, rule := range rules {
+			if inWindow(rule.Weekdays, rule.Start, rule.End, at) {
+				live = append(live, rule)
+			}
+		}
+		return live
+	}
+
+	for i := range order.Foods {
+		line := &order.Foods[i]
+		if keptFoods[i] {
+			continue
+		}
+		line.PricingRule = nil
+		if line.Combo != nil {
+			continue
+		}
+		rule, price := bestRule(liveAt(line.AddedAt), func(rule models.PricingRule) bool {
+			return ruleCovers(rule, rule.FoodIds, line.FoodId, line.Category)
+		}, line.Price, modifierExtras(line.Modifiers))
+		if rule == nil {
+			continue
+		}
+		line.PricingRule = &models.AppliedPricingRule{RuleId: rule.Id, Name: rule.Name, ListPrice: line.Price}
+		line.Price = price
+		line.TotalPrice = price * line.Quantity
+	}
+	for i := range order.Drinks {
+		line := &order.Drinks[i]
+		if keptDrinks[i] {
+			continue
+		}
+		line.PricingRule = nil
+		if line.Combo != nil {
+			continue
+		}
+		rule, price := bestRule(liveAt(line.AddedAt), func(rule models.PricingRule) bool {
+			return ruleCovers(rule, rule.DrinkIds, line.DrinkId, line.Category)
+		}, line.Price, modifierExtras(line.Modifiers))
+		if rule == nil {
+			continue
+		}
+		line.PricingRule = &models.AppliedPricingRule{RuleId: rule.Id, Name: rule.Name, ListPrice: line.Price}
+		line.Price = price
+		line.TotalPrice = price * line.Quantity
+	}
+}
+
+// stampLines sets when each food and drink line of an order was added. A line
+// that was already on the order before, with the same item, seat, course,
+// modifiers and note, is unchanged: it keeps its time and the name, category
+// and unit price stored on it, so catalog changes do not reach open checks.
+// Any other line is added at at. Times and prices sent by the client are
+// ignored. It reports which lines are unchanged; combo lines never are, they
+// are priced from their combo.
+func stampLines(order, before *models.Order, at time.Time) (keptFoods, keptDrinks []bool) {
+	foods := make(map[string][]models.FoodOrder)
+	drinks := make(map[string][]models.DrinkOrder)
+	if before != nil {
+		for _, line := range before.Foods {
+			if line.Combo == nil {
+				key := foodLineKey(line)
+				foods[key] = append(foods[key], line)
+			}
+		}
+		for _, line := range before.Drinks {
+			if line.Combo == nil {
+				key := drinkLineKey(line)
+				drinks[key] = append(drinks[key], line)
+			}
+		}
+	}
+	addedAt := func(stored time.Time) time.Time {
+		if stored.IsZero() {
+			// lines stored before they were dated count from the order
+			return before.CreatedAt
+		}
+		return stored
+	}
+
+	keptFoods = make([]bool, len(order.Foods))
+	for i := range order.Foods {
+		line := &order.Foods[i]
+		line.AddedAt = at
+		key := foodLineKey(*line)
+		if line.Combo != nil || len(foods[key]) == 0 {
+			continue
+		}
+		stored := foods[key][0]
+		foods[key] = foods[key][1:]
+		line.AddedAt = addedAt(stored.AddedAt)
+		line.FoodName, line.Category = stored.FoodName, stored.Category
+		line.Price, line.PricingRule, line.Modifiers = stored.Price, stored.PricingRule, stored.Modifiers
+		keptFoods[i] = true
+	}
+	keptDrinks = make([]bool, len(order.Drinks))
+	for i := range order.Drinks {
+		line := &order.Drinks[i]
+		line.AddedAt = at
+		key := drinkLineKey(*line)
+		if line.Combo != nil || len(drinks[key]) == 0 {
+			continue
+		}
+		stored := drinks[key][0]
+		drinks[key] = drinks[key][1:]
+		line.AddedAt = addedAt(stored.AddedAt)
+		line.DrinkName, line.Category = stored.DrinkName, stored.Category
+		line.Price, line.PricingRule, line.Modifiers = stored.Price, stored.PricingRule, stored.Modifiers
+		keptDrinks[i] = true
+	}
+	return keptFoods, keptDrinks
+}
+
+func foodLineKey(line models.FoodOrder) string {
+	return fmt.Sprint("food", line.FoodId.Hex(), line.Seat, line.Course, line.Note, modifiersKey(line.Modifiers))
+}
+
+func drinkLineKey(line models.DrinkOrder) string {
+	return fmt.Sprint("drink", line.DrinkId.Hex(), line.Variant, line.Seat, line.Course, line.Note, modifiersKey(line.Modifiers))
+}
+
+// modifiersKey names the chosen options of a line, leaving out their prices.
+func modifiersKey(modifiers []models.ChosenModifier) string {
+	var key strings.Builder
+	for _, modifier := range modifiers {
+		key.WriteString(modifier.Group + "=" + modifier.Option + ";")
+	}
+	return key.String()
+}
+
+func (usecase *UsecaseImplemented) CreatePricingRule(rule *models.PricingRule, employeeId primitive.ObjectID) error {
+	if err := validatePricingRule(rule); err != nil {
+		return err
+	}
+	rule.CreatedBy = employeeId
+	rule.CreatedAt = time.Now().UTC()
+	rule.UpdatedAt = rule.CreatedAt
+	return usecase.Repo.CreatePricingRule(rule)
+}
+
+// UpdatePricingRule replaces a rule's scope, window and price change, and
+// turns it on or off.
+func (usecase *UsecaseImplemented) UpdatePricingRule(rule *models.PricingRule) (*models.PricingRule, error) {
+	existing, err := usecase.GetPricingRuleById(rule.Id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+	rule.CreatedBy = existing.CreatedBy
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now().UTC()
+	if err := usecase.Repo.UpdatePricingRule(rule); err != nil {
+		return nil, ErrPricingRuleNotFound
+	}
+	return rule, nil
+}
+
+func (usecase *UsecaseImplemented) DeletePricingRule(id string) error {
+	if err := usecase.Repo.DeletePricingRule(id); err != nil {
+		return ErrPricingRuleNotFound
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) GetPricingRuleById(id string) (*models.PricingRule, error) {
+	rule, err := usecase.Repo.GetPricingRuleById(id)
+	if err != nil {
+		return nil, ErrPricingRuleNotFound
+	}
+	return rule, nil
+}
+
+func (usecase *UsecaseImplemented) GetPricingRules() ([]models.PricingRule, error) {
+	return usecase.Repo.GetPricingRules(false)
+}
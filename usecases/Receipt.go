@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/infrastructures/receipt_services"
+	"github.com/yesetoda/kushena/models"
+)
+
+// GetReceipt builds the guest receipt of an order, numbering it the first time
+// it is printed.
+func (usecase *UsecaseImplemented) GetReceipt(orderId string, employeeId primitive.ObjectID) (*receipt_services.Receipt, error) {
+	order, err := usecase.Repo.GetOrderById(orderId)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status == models.OrderCancelled || order.Status == models.OrderVoided || order.Status == models.OrderMerged {
+		return nil, ErrOrderClosed
+	}
+	payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	issued, err := usecase.Repo.IssueReceipt(order.Id, employeeId)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := receipt_services.Receipt{
+		Restaurant:    receipt_services.RestaurantFromEnv(),
+		Number:        issued.Number,
+		IssuedAt:      issued.IssuedAt,
+		OrderType:     order.OrderType(),
+		TableNumber:   order.TableNumber,
+		PickupAt:      order.PickupAt,
+		Subtotal:      order.Subtotal,
+		Discount:      order.DiscountTotal,
+		TaxInclusive:  order.TaxInclusive,
+		ServiceCharge: order.ServiceCharge,
+		DeliveryFee:   order.DeliveryFee,
+		Total:         order.TotalPrice,
+		Location:      settings.Location(),
+	}
+	if order.Delivery != nil {
+		receipt.DeliverTo = order.Delivery.CustomerName + ", " + order.Delivery.Address
+	}
+	if receipt.Subtotal == 0 {
+		// orders taken before the totals engine only carry a total
+		receipt.Subtotal = order.TotalPrice
+	}
+	for _, tax := range order.Taxes {
+		receipt.Taxes = append(receipt.Taxes, receipt_services.TaxLine{
+			Name:   tax.Name,
+			Rate:   tax.Rate,
+			Amount: tax.Amount,
+		})
+	}
+	for _, combo := range order.Combos {
+		receipt.Lines = append(receipt.Lines, receipt_services.Line{
+			Name:     combo.ComboName,
+			Details:  comboDetails(order, combo.LineId),
+			Quantity: combo.Quantity,
+			Price:    roundMoney(combo.TotalPrice / combo.Quantity),
+			Total:    combo.TotalPrice,
+		})
+	}
+	for _, food := range order.Foods {
+		if food.Combo != nil {
+			continue
+		}
+		receipt.Lines = append(receipt.Lines, receipt_services.Line{
+			Name:     food.FoodName,
+			Details:  modifierDetails(food.Modifiers),
+			Quantity: food.Quantity,
+			Price:    food.Price,
+			Total:    food.TotalPrice,
+		})
+	}
+	for _, drink := range order.Drinks {
+		if drink.Combo != nil {
+			continue
+		}
+		name := drink.DrinkName
+		if drink.Variant != "" {
+			name += " (" + drink.Variant + ")"
+		}
+		receipt.Lines = append(receipt.Lines, receipt_services.Line{
+			Name:     name,
+			Details:  modifierDetails(drink.Modifiers),
+			Quantity: drink.Quantity,
+			Price:    drink.Price,
+			Total:    drink.TotalPrice,
+		})
+	}
+	for _, payment := range payments {
+		receipt.Payments = append(receipt.Payments, receipt_services.PaymentLine{
+			Method:   payment.Method,
+			Amount:   payment.Net(),
+			Tendered: payment.Tendered,
+			Change:   payment.Change,
+		})
+	}
+	return &receipt, nil
+}
+
+// comboDetails lists the items chosen in a combo, with their modifiers.
+func comboDetails(order *models.Order, lineId primitive.ObjectID) []string {
+	var details []string
+	for _, food := range order.Foods {
+		if food.Combo != nil && food.Combo.LineId == lineId {
+			details = append(details, food.FoodName)
+			details = append(details, modifierDetails(food.Modifiers)...)
+		}
+	}
+	for _, drink := range order.Drinks {
+		if drink.Combo != nil && drink.Combo.LineId == lineId {
+			name := drink.DrinkName
+			if drink.Variant != "" {
+				name += " (" + drink.Variant + ")"
+			}
+			details = append(details, name)
+			details = append(details, modifierDetails(drink.Modifiers)...)
+		}
+	}
+	return details
+}
+
+func modifierDetails(modifiers []models.ChosenModifier) []string {
+	var details []string
+	for _, modifier := range modifiers {
+		details = append(details, modifier.Option)
+	}
+	return details
+}
@@ -0,0 +1,270 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// maxReservationMinutes caps how long one booking may hold a table.
+const maxReservationMinutes = 12 * 60
+
+// validateReservation checks the booking details and works out when it ends.
+func validateReservation(reservation *models.Reservation) error {
+	if reservation.GuestName == "" || reservation.Phone == "" {
+		return fmt.Errorf("%w: guest name and phone are required", ErrInvalidReservation)
+	}
+	if reservation.PartySize <= 0 {
+		return fmt.Errorf("%w: party size must be positive", ErrInvalidReservation)
+	}
+	if reservation.StartsAt.IsZero() {
+		return fmt.Errorf("%w: a start time is required", ErrInvalidReservation)
+	}
+	if reservation.DurationMinutes == 0 {
+		reservation.DurationMinutes = models.DefaultReservationMinutes
+	}
+	if reservation.DurationMinutes < 0 || reservation.DurationMinutes > maxReservationMinutes {
+		return fmt.Errorf("%w: duration must be between 1 and %d minutes", ErrInvalidReservation, maxReservationMinutes)
+	}
+	reservation.EndsAt = reservation.StartsAt.Add(time.Duration(reservation.DurationMinutes) * time.Minute)
+	return nil
+}
+
+// tableAvailable reports why a table cannot take a reservation, or nil if it
+// can: it must exist, seat the party and have no other active booking that
+// overlaps.
+func (usecase *UsecaseImplemented) tableAvailable(reservation *models.Reservation, number int) error {
+	table, err := usecase.Repo.GetTableByNumber(number)
+	if err != nil {
+		return ErrTableNotFound
+	}
+	if table.Capacity < reservation.PartySize {
+		return fmt.Errorf("%w: table %d seats %d, party is %d", ErrInvalidReservation, number, table.Capacity, reservation.PartySize)
+	}
+	overlapping, err := usecase.Repo.GetOverlappingReservations(number, reservation.StartsAt, reservation.EndsAt)
+	if err != nil {
+		return err
+	}
+	for _, other := range overlapping {
+		if other.Id != reservation.Id {
+			return fmt.Errorf("%w: table %d is booked from %s to %s", ErrTableBooked, number,
+				other.StartsAt.Local().Format("15:04"), other.EndsAt.Local().Format("15:04"))
+		}
+	}
+	return nil
+}
+
+// pickTable finds the smallest table that seats the party and is free for the
+// whole booking, preferring the requested section.
+func (usecase *UsecaseImplemented) pickTable(reservation *models.Reservation) (int, error) {
+	tables, err := usecase.Repo.GetAllTables()
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		iPreferred := tables[i].Section == reservation.Section
+		jPreferred := tables[j].Section == reservation.Section
+		if iPreferred != jPreferred {
+			return iPreferred
+		}
+		if tables[i].Capacity != tables[j].Capacity {
+			return tables[i].Capacity < tables[j].Capacity
+		}
+		return tables[i].Number < tables[j].Number
+	})
+	for _, table := range tables {
+		if table.Capacity < reservation.PartySize {
+			continue
+		}
+		if usecase.tableAvailable(reservation, table.Number) == nil {
+			return table.Number, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no table seats %d at that time", ErrTableBooked, reservation.PartySize)
+}
+
+// claimTable checks again after a write that no booking made at the same
+// moment took the table. Of two overlapping bookings the older one keeps it.
+func (usecase *UsecaseImplemented) claimTable(reservation *models.Reservation) error {
+	overlapping, err := usecase.Repo.GetOverlappingReservations(reservation.TableNumber, reservation.StartsAt, reservation.EndsAt)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 && overlapping[0].Id != reservation.Id {
+		return fmt.Errorf("%w: table %d was just booked", ErrTableBooked, reservation.TableNumber)
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) CreateReservation(reservation *models.Reservation, employeeId primitive.ObjectID) error {
+	if err := validateReservation(reservation); err != nil {
+		return err
+	}
+	if reservation.StartsAt.Before(time.Now()) {
+		return fmt.Errorf("%w: start time is in the past", ErrInvalidReservation)
+	}
+	if reservation.TableNumber != 0 {
+		if err := usecase.tableAvailable(reservation, reservation.TableNumber); err != nil {
+			return err
+		}
+	}
+	now := time.Now().UTC()
+	reservation.Status = models.ReservationPending
+	reservation.StatusHistory = []models.StatusChange{{To: models.ReservationPending, EmployeeId: employeeId, At: now}}
+	reservation.CreatedBy = employeeId
+	reservation.CreatedAt = now
+	if err := usecase.Repo.CreateReservation(reservation); err != nil {
+		return err
+	}
+	if reservation.TableNumber == 0 {
+		return nil
+	}
+	if err := usecase.claimTable(reservation); err != nil {
+		usecase.dropReservation(reservation, employeeId)
+		return err
+	}
+	return nil
+}
+
+// dropReservation cancels a booking that lost its table to a concurrent one.
+func (usecase *UsecaseImplemented) dropReservation(reservation *models.Reservation, employeeId primitive.ObjectID) {
+	change := models.StatusChange{From: reservation.Status, To: models.ReservationCancelled, EmployeeId: employeeId, At: time.Now().UTC()}
+	if err := usecase.Repo.UpdateReservationStatus(reservation.Id, change, 0); err != nil {
+		log.Printf("failed to drop double-booked reservation %s: %v", reservation.Id.Hex(), err)
+	}
+}
+
+// UpdateReservation changes the details of a pending or confirmed booking.
+// Its status only changes through the confirm, seat, no-show and cancel actions.
+func (usecase *UsecaseImplemented) UpdateReservation(reservation *models.Reservation) error {
+	existing, err := usecase.Repo.GetReservationById(reservation.Id.Hex())
+	if err != nil {
+		return ErrReservationNotFound
+	}
+	if existing.Status != models.ReservationPending && existing.Status != models.ReservationConfirmed {
+		return fmt.Errorf("%w: a %s reservation cannot be changed", ErrInvalidReservation, existing.Status)
+	}
+	if err := validateReservation(reservation); err != nil {
+		return err
+	}
+	reservation.Status = existing.Status
+	reservation.StatusHistory = existing.StatusHistory
+	reservation.CreatedBy = existing.CreatedBy
+	reservation.CreatedAt = existing.CreatedAt
+	if reservation.TableNumber != 0 {
+		if err := usecase.tableAvailable(reservation, reservation.TableNumber); err != nil {
+			return err
+		}
+	}
+	if err := usecase.Repo.UpdateReservation(reservation); err != nil {
+		return err
+	}
+	if reservation.TableNumber == 0 {
+		return nil
+	}
+	if err := usecase.claimTable(reservation); err != nil {
+		// put the booking back the way it was
+		if err := usecase.Repo.UpdateReservation(existing); err != nil {
+			log.Printf("failed to restore reservation %s: %v", existing.Id.Hex(), err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) GetReservationById(id string) (*models.Reservation, error) {
+	reservation, err := usecase.Repo.GetReservationById(id)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// GetDailyReservations lists the bookings of the day, in the restaurant's
+// timezone and today when zero, for the host stand, earliest first.
+func (usecase *UsecaseImplemented) GetDailyReservations(day time.Time) ([]models.Reservation, error) {
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	from := businessDay(day, settings.Location())
+	return usecase.Repo.GetReservations(from, from.AddDate(0, 0, 1))
+}
+
+// ConfirmReservation confirms a booking on a table: the one given, the one
+// already on the booking, or the best free one.
+func (usecase *UsecaseImplemented) ConfirmReservation(id string, tableNumber int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+	return usecase.moveReservation(id, models.ReservationConfirmed, tableNumber, employeeId)
+}
+
+// SeatReservation seats the party at its table and marks the table occupied.
+func (usecase *UsecaseImplemented) SeatReservation(id string, tableNumber int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+	reservation, err := usecase.moveReservation(id, models.ReservationSeated, tableNumber, employeeId)
+	if err != nil {
+		return nil, err
+	}
+	usecase.occupyTable(reservation.TableNumber)
+	return reservation, nil
+}
+
+func (usecase *UsecaseImplemented) NoShowReservation(id string, employeeId primitive.ObjectID) (*models.Reservation, error) {
+	return usecase.moveReservation(id, models.ReservationNoShow, 0, employeeId)
+}
+
+func (usecase *UsecaseImplemented) CancelReservation(id string, employeeId primitive.ObjectID) (*models.Reservation, error) {
+	return usecase.moveReservation(id, models.ReservationCancelled, 0, employeeId)
+}
+
+// moveReservation moves a booking to status. Confirming and seating need a
+// table, so one is checked or picked for them.
+func (usecase *UsecaseImplemented) moveReservation(id, status string, tableNumber int, employeeId primitive.ObjectID) (*models.Reservation, error) {
+	reservation, err := usecase.Repo.GetReservationById(id)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	if !models.CanTransitionReservation(reservation.Status, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, reservation.Status, status)
+	}
+
+	needsTable := status == models.ReservationConfirmed || status == models.ReservationSeated
+	assign := 0
+	if needsTable {
+		switch {
+		case tableNumber != 0:
+			assign = tableNumber
+		case reservation.TableNumber != 0:
+			assign = reservation.TableNumber
+		default:
+			if assign, err = usecase.pickTable(reservation); err != nil {
+				return nil, err
+			}
+		}
+		if err := usecase.tableAvailable(reservation, assign); err != nil {
+			return nil, err
+		}
+	}
+
+	change := models.StatusChange{From: reservation.Status, To: status, EmployeeId: employeeId, At: time.Now().UTC()}
+	if err := usecase.Repo.UpdateReservationStatus(reservation.Id, change, assign); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+	reservation.Status = status
+	reservation.StatusHistory = append(reservation.StatusHistory, change)
+	if assign != 0 && assign != reservation.TableNumber {
+		previous := reservation.TableNumber
+		reservation.TableNumber = assign
+		if err := usecase.claimTable(reservation); err != nil {
+			undo := models.StatusChange{From: status, To: change.From, EmployeeId: employeeId, At: time.Now().UTC()}
+			if err := usecase.Repo.UpdateReservationStatus(reservation.Id, undo, previous); err != nil {
+				log.Printf("failed to undo reservation %s: %v", reservation.Id.Hex(), err)
+			}
+			return nil, err
+		}
+	}
+	return reservation, nil
+}
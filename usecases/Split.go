@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// maxSplitParts is the most parts a check can be split into.
+const maxSplitParts = 50
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// evenShares divides total into n amounts that add back up to total exactly;
+// the last share absorbs the rounding remainder.
+func evenShares(total float64, n int) []float64 {
+	shares := make([]float64, n)
+	share := roundMoney(total / float64(n))
+	for i := 0; i < n-1; i++ {
+		shares[i] = share
+	}
+	shares[n-1] = roundMoney(total - share*float64(n-1))
+	return shares
+}
+
+// scaleToTotal rescales parts priced from their lines so that together they
+// come to the order total, which carries discounts, tax and service charge.
+// The last part absorbs the rounding remainder.
+func scaleToTotal(split []models.BillPart, total float64) {
+	raw := 0.0
+	for _, part := range split {
+		raw += part.Amount
+	}
+	if raw == 0 {
+		for i, amount := range evenShares(total, len(split)) {
+			split[i].Amount = amount
+		}
+		return
+	}
+	assigned := 0.0
+	for i := range split {
+		if i == len(split)-1 {
+			split[i].Amount = roundMoney(total - assigned)
+			break
+		}
+		split[i].Amount = roundMoney(total * split[i].Amount / raw)
+		assigned += split[i].Amount
+	}
+}
+
+func splitEven(order *models.Order, parts int) ([]models.BillPart, error) {
+	if parts < 2 {
+		return nil, fmt.Errorf("%w: an even split needs at least 2 parts", ErrInvalidSplit)
+	}
+	if parts > maxSplitParts {
+		return nil, fmt.Errorf("%w: a check splits into at most %d parts", ErrInvalidSplit, maxSplitParts)
+	}
+	var split []models.BillPart
+	for i, amount := range evenShares(order.TotalPrice, parts) {
+		split = append(split, models.BillPart{Number: i + 1, Amount: amount})
+	}
+	return split, nil
+}
+
+func splitByItems(order *models.Order, items []models.SplitItems) ([]models.BillPart, error) {
+	if len(items) < 2 {
+		return nil, fmt.Errorf("%w: an item split needs at least 2 parts", ErrInvalidSplit)
+	}
+	if len(items) > maxSplitParts {
+		return nil, fmt.Errorf("%w: a check splits into at most %d parts", ErrInvalidSplit, maxSplitParts)
+	}
+	foodTaken := make([]bool, len(order.Foods))
+	drinkTaken := make([]bool, len(order.Drinks))
+	var split []models.BillPart
+	for i, pick := range items {
+		part := models.BillPart{Number: i + 1}
+		for _, idx := range pick.Foods {
+			if idx < 0 || idx >= len(order.Foods) || foodTaken[idx] {
+				return nil, fmt.Errorf("%w: food line %d is missing or used twice", ErrInvalidSplit, idx)
+			}
+			foodTaken[idx] = true
+			part.Foods = append(part.Foods, order.Foods[idx])
+			part.Amount += order.Foods[idx].TotalPrice - order.Foods[idx].DiscountAmount
+		}
+		for _, idx := range pick.Drinks {
+			if idx < 0 || idx >= len(order.Drinks) || drinkTaken[idx] {
+				return nil, fmt.Errorf("%w: drink line %d is missing or used twice", ErrInvalidSplit, idx)
+			}
+			drinkTaken[idx] = true
+			part.Drinks = append(part.Drinks, order.Drinks[idx])
+			part.Amount += order.Drinks[idx].TotalPrice - order.Drinks[idx].DiscountAmount
+		}
+		split = append(split, part)
+	}
+	for i, taken := range foodTaken {
+		if !taken {
+			return nil, fmt.Errorf("%w: food line %d is not assigned to a part", ErrInvalidSplit, i)
+		}
+	}
+	for i, taken := range drinkTaken {
+		if !taken {
+			return nil, fmt.Errorf("%w: drink line %d is not assigned to a part", ErrInvalidSplit, i)
+		}
+	}
+	scaleToTotal(split, order.TotalPrice)
+	return split, nil
+}
+
+// splitBySeat gives every seat its own lines. Lines without a seat are shared
+// and their cost is divided evenly across the seats.
+func splitBySeat(order *models.Order) ([]models.BillPart, error) {
+	bySeat := make(map[int]*models.BillPart)
+	shared := 0.0
+	for _, food := range order.Foods {
+		if food.Seat == 0 {
+			shared += food.TotalPrice - food.DiscountAmount
+			continue
+		}
+		if bySeat[food.Seat] == nil {
+			bySeat[food.Seat] = &models.BillPart{Seat: food.Seat}
+		}
+		bySeat[food.Seat].Foods = append(bySeat[food.Seat].Foods, food)
+		bySeat[food.Seat].Amount += food.TotalPrice - food.DiscountAmount
+	}
+	for _, drink := range order.Drinks {
+		if drink.Seat == 0 {
+			shared += drink.TotalPrice - drink.DiscountAmount
+			continue
+		}
+		if bySeat[drink.Seat] == nil {
+			bySeat[drink.Seat] = &models.BillPart{Seat: drink.Seat}
+		}
+		bySeat[drink.Seat].Drinks = append(bySeat[drink.Seat].Drinks, drink)
+		bySeat[drink.Seat].Amount += drink.TotalPrice - drink.DiscountAmount
+	}
+	if len(bySeat) < 2 {
+		return nil, fmt.Errorf("%w: a seat split needs lines on at least 2 seats", ErrInvalidSplit)
+	}
+	seats := make([]int, 0, len(bySeat))
+	for seat := range bySeat {
+		seats = append(seats, seat)
+	}
+	sort.Ints(seats)
+	shares := evenShares(shared, len(seats))
+	var split []models.BillPart
+	for i, seat := range seats {
+		part := bySeat[seat]
+		part.Number = i + 1
+		part.Amount = part.Amount + shares[i]
+		split = append(split, *part)
+	}
+	scaleToTotal(split, order.TotalPrice)
+	return split, nil
+}
+
+// SplitOrder divides an open order's check into parts that are settled one by
+// one. An order can be split again until its first part is settled, and not
+// at all once a payment has been taken against the whole check.
+func (usecase *UsecaseImplemented) SplitOrder(id string, request models.SplitRequest, employeeId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	if order.HasSettledPart() {
+		return nil, fmt.Errorf("%w: part of the check is already settled", ErrInvalidSplit)
+	}
+	payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) > 0 {
+		return nil, fmt.Errorf("%w: payments have already been taken on this order", ErrInvalidSplit)
+	}
+
+	var split []models.BillPart
+	switch request.Mode {
+	case models.SplitEven:
+		split, err = splitEven(order, request.Parts)
+	case models.SplitByItems:
+		split, err = splitByItems(order, request.Items)
+	case models.SplitBySeat:
+		split, err = splitBySeat(order)
+	default:
+		err = fmt.Errorf("%w: unknown mode %q", ErrInvalidSplit, request.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	before := *order
+	order.SplitMode = request.Mode
+	order.Splits = split
+	if err := usecase.Repo.UpdateOrder(order); err != nil {
+		return nil, orderWriteError(err)
+	}
+	usecase.recordChange(&before, order, employeeId)
+	usecase.publishOrderEvent(models.EventOrderUpdated, *order)
+	return order, nil
+}
+
+// SettleBillPart settles one part of a split check once the payments recorded
+// against it, net of refunds, cover it. Once every part is settled and the
+// order has been served, the order itself moves to paid.
+func (usecase *UsecaseImplemented) SettleBillPart(id string, number int, employeeId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+	if err != nil {
+		return nil, err
+	}
+	due, err := amountDue(order, number, payments)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+	}
+	if due > 0 {
+		return nil, fmt.Errorf("%w: %.2f is still to be paid on part %d", ErrInvalidSplit, due, number)
+	}
+	if err := usecase.Repo.SettleBillPart(id, number, employeeId, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+	}
+	before := order
+	order, err = usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	usecase.recordChange(before, order, employeeId)
+	if !order.Unsettled() && order.Status == models.OrderServed {
+		return usecase.TransitionOrder(id, models.OrderPaid, employeeId)
+	}
+	usecase.publishOrderEvent(models.EventOrderUpdated, *order)
+	return order, nil
+}
@@ -0,0 +1,288 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+func (usecase *UsecaseImplemented) CreateTable(table *models.Table) error {
+	if table.Number <= 0 || table.Capacity <= 0 {
+		return fmt.Errorf("%w: number and capacity must be positive", ErrInvalidTable)
+	}
+	if table.Status == "" {
+		table.Status = models.TableFree
+	}
+	if err := usecase.checkWaiter(table.WaiterId); err != nil {
+		return err
+	}
+	if !models.ValidTableStatus(table.Status) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidTable, table.Status)
+	}
+	return usecase.Repo.CreateTable(table)
+}
+
+func (usecase *UsecaseImplemented) UpdateTable(table *models.Table) error {
+	if table.Number <= 0 || table.Capacity <= 0 {
+		return fmt.Errorf("%w: number and capacity must be positive", ErrInvalidTable)
+	}
+	if !models.ValidTableStatus(table.Status) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidTable, table.Status)
+	}
+	if err := usecase.checkWaiter(table.WaiterId); err != nil {
+		return err
+	}
+	existing, err := usecase.Repo.GetTableById(table.Id.Hex())
+	if err != nil {
+		return ErrTableNotFound
+	}
+	// the guest token only rotates through IssueGuestToken and releaseTable
+	table.GuestTokenGeneration = existing.GuestTokenGeneration
+	return usecase.Repo.UpdateTable(table)
+}
+
+// checkWaiter confirms that a waiter assigned to a table is an employee.
+func (usecase *UsecaseImplemented) checkWaiter(waiterId primitive.ObjectID) error {
+	if waiterId.IsZero() {
+		return nil
+	}
+	if _, err := usecase.Repo.GetEmployeeById(waiterId.Hex()); err != nil {
+		return fmt.Errorf("%w: waiter %s is not an employee", ErrInvalidTable, waiterId.Hex())
+	}
+	return nil
+}
+
+func (usecase *UsecaseImplemented) DeleteTable(id string) error {
+	table, err := usecase.Repo.GetTableById(id)
+	if err != nil {
+		return ErrTableNotFound
+	}
+	open, err := usecase.Repo.GetOpenOrdersByTable(table.Number)
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: table %d has open orders", ErrTableInUse, table.Number)
+	}
+	return usecase.Repo.DeleteTable(id)
+}
+
+func (usecase *UsecaseImplemented) GetTableById(id string) (*models.Table, error) {
+	return usecase.Repo.GetTableById(id)
+}
+
+func (usecase *UsecaseImplemented) GetAllTables() ([]models.Table, error) {
+	return usecase.Repo.GetAllTables()
+}
+
+// occupyTable marks a table occupied once an order is opened on it.
+func (usecase *UsecaseImplemented) occupyTable(number int) {
+	if err := usecase.Repo.SetTableStatus(number, models.TableOccupied); err != nil {
+		log.Printf("failed to mark table %d occupied: %v", number, err)
+	}
+}
+
+// releaseTable frees a table when its last open order is closed. Takeaway and
+// delivery orders have no table.
+func (usecase *UsecaseImplemented) releaseTable(number int) {
+	if number == 0 {
+		return
+	}
+	open, err := usecase.Repo.GetOpenOrdersByTable(number)
+	if err != nil {
+		log.Printf("failed to check open orders of table %d: %v", number, err)
+		return
+	}
+	if len(open) > 0 {
+		return
+	}
+	if err := usecase.Repo.SetTableStatus(number, models.TableFree); err != nil {
+		log.Printf("failed to free table %d: %v", number, err)
+	}
+	// the next party gets a fresh token
+	usecase.rotateGuestToken(number)
+}
+
+// TransferOrder moves an open order to another table.
+func (usecase *UsecaseImplemented) TransferOrder(id string, tableNumber int, employeeId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	if order.OrderType() != models.OrderDineIn {
+		return nil, fmt.Errorf("%w: %s orders are not served at a table", ErrInvalidOrder, order.OrderType())
+	}
+	if order.TableNumber == tableNumber {
+		return order, nil
+	}
+	if _, err := usecase.Repo.GetTableByNumber(tableNumber); err != nil {
+		return nil, ErrTableNotFound
+	}
+	before := *order
+	from := order.TableNumber
+	order.TableNumber = tableNumber
+	if err := usecase.Repo.UpdateOrder(order); err != nil {
+		return nil, orderWriteError(err)
+	}
+	usecase.recordChange(&before, order, employeeId)
+	usecase.occupyTable(tableNumber)
+	usecase.releaseTable(from)
+	usecase.publishOrderEvent(models.EventOrderUpdated, *order)
+	return order, nil
+}
+
+// MergeTables folds every open order of table from and table to into a single
+// check on table to. The oldest open order on table to (or on table from if to
+// has none) keeps the lines; the others are closed as merged into it once the
+// merged check is saved, and if closing one fails every write is undone.
+// Orders with payments, waiting for confirmation or with a void pending are
+// not merged.
+func (usecase *UsecaseImplemented) MergeTables(from, to int, employeeId primitive.ObjectID) (*models.Order, error) {
+	if from == to {
+		return nil, fmt.Errorf("%w: cannot merge a table with itself", ErrInvalidTable)
+	}
+	for _, number := range []int{from, to} {
+		if _, err := usecase.Repo.GetTableByNumber(number); err != nil {
+			return nil, fmt.Errorf("%w: table %d", ErrTableNotFound, number)
+		}
+	}
+	fromOrders, err := usecase.Repo.GetOpenOrdersByTable(from)
+	if err != nil {
+		return nil, err
+	}
+	if len(fromOrders) == 0 {
+		return nil, fmt.Errorf("%w: table %d has no open orders", ErrOrderNotFound, from)
+	}
+	toOrders, err := usecase.Repo.GetOpenOrdersByTable(to)
+	if err != nil {
+		return nil, err
+	}
+	orders := append(toOrders, fromOrders...)
+	for _, order := range orders {
+		if order.HasSettledPart() {
+			return nil, fmt.Errorf("%w: order %s has a settled part", ErrOrderClosed, order.Id.Hex())
+		}
+		if order.Status == models.OrderNeedsConfirmation {
+			return nil, fmt.Errorf("%w: order %s still needs confirmation", ErrInvalidTransition, order.Id.Hex())
+		}
+		if order.VoidPending() {
+			return nil, fmt.Errorf("%w: order %s has a void waiting for approval", ErrInvalidTransition, order.Id.Hex())
+		}
+		payments, err := usecase.Repo.GetPaymentsByOrder(order.Id)
+		if err != nil {
+			return nil, err
+		}
+		if len(payments) > 0 {
+			return nil, fmt.Errorf("%w: order %s already has payments", ErrOrderClosed, order.Id.Hex())
+		}
+	}
+	target := orders[0]
+	targetBefore := target
+	target.TableNumber = to
+	// the merged check has to be split again
+	target.SplitMode = ""
+	target.Splits = nil
+	for _, order := range orders[1:] {
+		target.Foods = append(target.Foods, order.Foods...)
+		target.Drinks = append(target.Drinks, order.Drinks...)
+		target.Combos = append(target.Combos, order.Combos...)
+	}
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	if err := ComputeTotals(&target, settings); err != nil {
+		return nil, err
+	}
+	set := pricedFields(&target)
+	set["table_number"] = to
+	if err := usecase.Repo.PatchOrder(target.Id, target.Version, set, bson.M{"split_mode": "", "splits": ""}); err != nil {
+		return nil, orderWriteError(err)
+	}
+	target.Version++
+
+	now := time.Now().UTC()
+	var closed []models.Order
+	for _, order := range orders[1:] {
+		order.StatusHistory = append(order.StatusHistory, models.StatusChange{
+			From:       order.Status,
+			To:         models.OrderMerged,
+			EmployeeId: employeeId,
+			At:         now,
+		})
+		order.Status = models.OrderMerged
+		order.MergedInto = target.Id
+		order.Foods = nil
+		order.Drinks = nil
+		order.Combos = nil
+		order.TotalPrice = 0
+		order.SplitMode = ""
+		order.Splits = nil
+		set := bson.M{
+			"status":         order.Status,
+			"status_history": order.StatusHistory,
+			"merged_into":    order.MergedInto,
+			"foods":          order.Foods,
+			"drinks":         order.Drinks,
+			"total_price":    order.TotalPrice,
+		}
+		if err := usecase.Repo.PatchOrder(order.Id, order.Version, set, bson.M{"combos": "", "split_mode": "", "splits": ""}); err != nil {
+			usecase.undoMerge(targetBefore, target, orders[1:len(closed)+1], closed)
+			return nil, orderWriteError(err)
+		}
+		order.Version++
+		closed = append(closed, order)
+	}
+
+	usecase.recordChange(&targetBefore, &target, employeeId)
+	for i := range closed {
+		usecase.recordChange(&orders[i+1], &closed[i], employeeId)
+		usecase.publishOrderEvent(models.EventStatusChanged, closed[i])
+	}
+	usecase.occupyTable(to)
+	usecase.releaseTable(from)
+	usecase.publishOrderEvent(models.EventOrderUpdated, target)
+	return &target, nil
+}
+
+// undoMerge puts back the merge target and the source orders already closed
+// when a merge fails part way. Nothing else writes to them in between unless
+// someone edits them at the same moment; what cannot be put back is logged.
+func (usecase *UsecaseImplemented) undoMerge(targetBefore, target models.Order, before, closed []models.Order) {
+	for i, order := range closed {
+		set := bson.M{
+			"status":         before[i].Status,
+			"status_history": before[i].StatusHistory,
+			"foods":          before[i].Foods,
+			"drinks":         before[i].Drinks,
+			"total_price":    before[i].TotalPrice,
+		}
+		if len(before[i].Combos) > 0 {
+			set["combos"] = before[i].Combos
+		}
+		if before[i].SplitMode != "" {
+			set["split_mode"] = before[i].SplitMode
+			set["splits"] = before[i].Splits
+		}
+		if err := usecase.Repo.PatchOrder(order.Id, order.Version, set, bson.M{"merged_into": ""}); err != nil {
+			log.Printf("failed to undo merge of order %s: %v", order.Id.Hex(), err)
+		}
+	}
+	set := pricedFields(&targetBefore)
+	set["table_number"] = targetBefore.TableNumber
+	if targetBefore.SplitMode != "" {
+		set["split_mode"] = targetBefore.SplitMode
+		set["splits"] = targetBefore.Splits
+	}
+	if err := usecase.Repo.PatchOrder(target.Id, target.Version, set, nil); err != nil {
+		log.Printf("failed to undo merge into order %s: %v", target.Id.Hex(), err)
+	}
+}
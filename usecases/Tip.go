@@ -0,0 +1,307 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// maxPayoutDays bounds how many days one tip payout may cover.
+const maxPayoutDays = 62
+
+// tipMethod reports whether tips can be left with a payment method; points
+// and vouchers only pay for the bill.
+func tipMethod(method string) bool {
+	switch method {
+	case models.PaymentCash, models.PaymentCard, models.PaymentMobileMoney:
+		return true
+	}
+	return false
+}
+
+// RecordTip records a tip left on an order apart from its payments.
+func (usecase *UsecaseImplemented) RecordTip(orderId string, request models.TipRequest, employeeId primitive.ObjectID) (*models.Tip, error) {
+	if request.Amount <= 0 {
+		return nil, fmt.Errorf("%w: tip must be positive", ErrInvalidPayment)
+	}
+	if !tipMethod(request.Method) {
+		return nil, fmt.Errorf("%w: tips are taken in cash, card or mobile money", ErrInvalidPayment)
+	}
+	order, err := usecase.Repo.GetOrderById(orderId)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status == models.OrderCancelled || order.Status == models.OrderVoided || order.Status == models.OrderMerged {
+		return nil, ErrOrderClosed
+	}
+	tip := models.Tip{
+		OrderId:    order.Id,
+		Amount:     roundMoney(request.Amount),
+		Method:     request.Method,
+		EmployeeId: employeeId,
+		At:         time.Now().UTC(),
+	}
+	if err := usecase.Repo.CreateTip(&tip); err != nil {
+		return nil, err
+	}
+	return &tip, nil
+}
+
+// GetOrderTips returns the tips left on an order, with its payments or apart.
+func (usecase *UsecaseImplemented) GetOrderTips(orderId string) ([]models.Tip, error) {
+	order, err := usecase.Repo.GetOrderById(orderId)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return usecase.Repo.GetOrderTips(order.Id)
+}
+
+// parseClock reads an "HH:MM" time of day.
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// validateTipPool checks the tip pool settings, defaulting to daily pools.
+func validateTipPool(pool *models.TipPoolSettings) error {
+	if pool.Period == "" {
+		pool.Period = models.TipPoolDaily
+	}
+	switch pool.Period {
+	case models.TipPoolDaily:
+	case models.TipPoolByShift:
+		if len(pool.Shifts) == 0 {
+			return fmt.Errorf("%w: shift tip pools need at least one shift", ErrInvalidSettings)
+		}
+		for _, shift := range pool.Shifts {
+			if shift.Name == "" {
+				return fmt.Errorf("%w: every shift needs a name", ErrInvalidSettings)
+			}
+			if _, err := parseClock(shift.Start); err != nil {
+				return fmt.Errorf("%w: shift %s start must be HH:MM", ErrInvalidSettings, shift.Name)
+			}
+			if _, err := parseClock(shift.End); err != nil {
+				return fmt.Errorf("%w: shift %s end must be HH:MM", ErrInvalidSettings, shift.Name)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: tip pool period must be day or shift", ErrInvalidSettings)
+	}
+	for role, weight := range pool.RoleWeights {
+		if weight < 0 {
+			return fmt.Errorf("%w: tip weight of %s cannot be negative", ErrInvalidSettings, role)
+		}
+	}
+	return nil
+}
+
+// tipPools lays out the empty pools of the days from first to last.
+func tipPools(settings models.TipPoolSettings, first, last time.Time) []models.TipPool {
+	var pools []models.TipPool
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if settings.Period != models.TipPoolByShift {
+			pools = append(pools, models.TipPool{Name: day.Format("2006-01-02"), From: day, To: day.AddDate(0, 0, 1)})
+			continue
+		}
+		year, month, date := day.Date()
+		at := func(clock time.Duration) time.Time {
+			return time.Date(year, month, date, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, day.Location())
+		}
+		for _, shift := range settings.Shifts {
+			start, _ := parseClock(shift.Start)
+			end, _ := parseClock(shift.End)
+			from := at(start)
+			to := at(end)
+			if !to.After(from) {
+				to = to.AddDate(0, 0, 1)
+			}
+			pools = append(pools, models.TipPool{Name: day.Format("2006-01-02") + " " + shift.Name, From: from, To: to})
+		}
+	}
+	return pools
+}
+
+// shiftSpan is one stretch an employee was checked in.
+type shiftSpan struct {
+	employeeId primitive.ObjectID
+	from, to   time.Time
+}
+
+// workedSpans pairs each check-in with the check-out that follows it. A
+// check-in with no check-out yet runs until now.
+func workedSpans(attendances []models.Attendance, now time.Time) []shiftSpan {
+	open := make(map[primitive.ObjectID]time.Time)
+	var spans []shiftSpan
+	for _, record := range attendances {
+		switch record.Type {
+		case "in":
+			if _, ok := open[record.EmployeeID]; !ok {
+				open[record.EmployeeID] = record.Time
+			}
+		case "out":
+			if in, ok := open[record.EmployeeID]; ok {
+				spans = append(spans, shiftSpan{record.EmployeeID, in, record.Time})
+				delete(open, record.EmployeeID)
+			}
+		}
+	}
+	for employeeId, in := range open {
+		spans = append(spans, shiftSpan{employeeId, in, now})
+	}
+	return spans
+}
+
+// overlap is how long [from, to) and [start, end) have in common.
+func overlap(from, to, start, end time.Time) time.Duration {
+	if start.After(from) {
+		from = start
+	}
+	if end.Before(to) {
+		to = end
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// shareTips splits a pool's tips by weighted hours, in cents, giving any cent
+// left over from rounding to the largest share so the payouts add up.
+func shareTips(pool *models.TipPool) {
+	weighted := 0.0
+	for _, share := range pool.Shares {
+		weighted += share.Hours * share.Weight
+	}
+	if weighted == 0 {
+		pool.Unallocated = pool.Tips
+		pool.Shares = []models.TipShare{}
+		return
+	}
+	paid := 0.0
+	for i := range pool.Shares {
+		pool.Shares[i].Payout = roundMoney(pool.Tips * pool.Shares[i].Hours * pool.Shares[i].Weight / weighted)
+		paid += pool.Shares[i].Payout
+	}
+	sort.Slice(pool.Shares, func(i, j int) bool {
+		if pool.Shares[i].Payout != pool.Shares[j].Payout {
+			return pool.Shares[i].Payout > pool.Shares[j].Payout
+		}
+		return pool.Shares[i].EmployeeId.Hex() < pool.Shares[j].EmployeeId.Hex()
+	})
+	pool.Shares[0].Payout = roundMoney(pool.Shares[0].Payout + pool.Tips - paid)
+}
+
+// TipPayout works out the tip pools of the days from first to last, in the
+// restaurant's timezone and today when zero, and what each employee is owed
+// from them. Hours come from check-in and check-out pairs, counted only while
+// they overlap the pool.
+func (usecase *UsecaseImplemented) TipPayout(first, last time.Time) (*models.TipPayout, error) {
+	settings, err := usecase.Repo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	first, last = businessDay(first, settings.Location()), businessDay(last, settings.Location())
+	if last.Before(first) {
+		return nil, fmt.Errorf("%w: to is before from", ErrInvalidQuery)
+	}
+	if last.After(first.AddDate(0, 0, maxPayoutDays)) {
+		return nil, fmt.Errorf("%w: a payout covers at most %d days", ErrInvalidQuery, maxPayoutDays)
+	}
+	pools := tipPools(settings.TipPool, first, last)
+	start, end := pools[0].From, pools[0].To
+	for _, pool := range pools {
+		if pool.From.Before(start) {
+			start = pool.From
+		}
+		if pool.To.After(end) {
+			end = pool.To
+		}
+	}
+
+	tips, err := usecase.Repo.GetTips(start, end)
+	if err != nil {
+		return nil, err
+	}
+	// a shift may have started the day before the first pool
+	attendances, err := usecase.Repo.GetAttendanceBetween(start.AddDate(0, 0, -1), end)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := usecase.Repo.GetAllEmployees()
+	if err != nil {
+		return nil, err
+	}
+	staff := make(map[primitive.ObjectID]models.Employee)
+	for _, employee := range employees {
+		staff[employee.Id] = employee
+	}
+
+	payout := models.TipPayout{From: first, To: last.AddDate(0, 0, 1), Pools: pools, Totals: []models.TipShare{}}
+	for _, tip := range tips {
+		pooled := false
+		for i := range payout.Pools {
+			if !tip.At.Before(payout.Pools[i].From) && tip.At.Before(payout.Pools[i].To) {
+				payout.Pools[i].Tips += tip.Amount
+				pooled = true
+				break
+			}
+		}
+		if !pooled {
+			payout.Unpooled += tip.Amount
+		}
+	}
+
+	spans := workedSpans(attendances, time.Now())
+	totals := make(map[primitive.ObjectID]*models.TipShare)
+	for i := range payout.Pools {
+		pool := &payout.Pools[i]
+		pool.Tips = roundMoney(pool.Tips)
+		payout.Tips += pool.Tips
+		hours := make(map[primitive.ObjectID]float64)
+		for _, span := range spans {
+			if worked := overlap(span.from, span.to, pool.From, pool.To); worked > 0 {
+				hours[span.employeeId] += worked.Hours()
+			}
+		}
+		pool.Shares = []models.TipShare{}
+		for employeeId, worked := range hours {
+			employee := staff[employeeId]
+			pool.Shares = append(pool.Shares, models.TipShare{
+				EmployeeId: employeeId,
+				Name:       employee.Name,
+				Role:       employee.Role,
+				Hours:      roundMoney(worked),
+				Weight:     settings.TipPool.WeightFor(employee.Role),
+			})
+		}
+		shareTips(pool)
+		for _, share := range pool.Shares {
+			total, ok := totals[share.EmployeeId]
+			if !ok {
+				total = &models.TipShare{EmployeeId: share.EmployeeId, Name: share.Name, Role: share.Role, Weight: share.Weight}
+				totals[share.EmployeeId] = total
+			}
+			total.Hours = roundMoney(total.Hours + share.Hours)
+			total.Payout = roundMoney(total.Payout + share.Payout)
+		}
+	}
+	payout.Tips = roundMoney(payout.Tips)
+	payout.Unpooled = roundMoney(payout.Unpooled)
+	for _, total := range totals {
+		payout.Totals = append(payout.Totals, *total)
+	}
+	sort.Slice(payout.Totals, func(i, j int) bool {
+		if payout.Totals[i].Payout != payout.Totals[j].Payout {
+			return payout.Totals[i].Payout > payout.Totals[j].Payout
+		}
+		return payout.Totals[i].EmployeeId.Hex() < payout.Totals[j].EmployeeId.Hex()
+	})
+	return &payout, nil
+}
@@ -0,0 +1,199 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// lineRef gives the totals engine the same view of food and drink lines.
+type lineRef struct {
+	gross          float64
+	category       string
+	discount       *models.Discount
+	discountAmount *float64
+	taxRate        *float64
+}
+
+func orderLines(order *models.Order) []lineRef {
+	var lines []lineRef
+	for i := range order.Foods {
+		food := &order.Foods[i]
+		lines = append(lines, lineRef{
+			gross:          food.TotalPrice,
+			category:       food.Category,
+			discount:       food.Discount,
+			discountAmount: &food.DiscountAmount,
+			taxRate:        &food.TaxRate,
+		})
+	}
+	for i := range order.Drinks {
+		drink := &order.Drinks[i]
+		lines = append(lines, lineRef{
+			gross:          drink.TotalPrice,
+			category:       drink.Category,
+			discount:       drink.Discount,
+			discountAmount: &drink.DiscountAmount,
+			taxRate:        &drink.TaxRate,
+		})
+	}
+	return lines
+}
+
+func validateDiscount(discount *models.Discount) error {
+	if discount == nil {
+		return nil
+	}
+	if discount.Reason == "" {
+		return fmt.Errorf("%w: a reason code is required", ErrInvalidDiscount)
+	}
+	switch discount.Type {
+	case models.DiscountPercent:
+		if discount.Value <= 0 || discount.Value > 100 {
+			return fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidDiscount)
+		}
+	case models.DiscountFixed:
+		if discount.Value <= 0 {
+			return fmt.Errorf("%w: amount must be positive", ErrInvalidDiscount)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidDiscount, discount.Type)
+	}
+	return nil
+}
+
+// discountOn returns how much a discount takes off amount, never more than amount.
+func discountOn(discount *models.Discount, amount float64) float64 {
+	if discount == nil {
+		return 0
+	}
+	off := discount.Value
+	if discount.Type == models.DiscountPercent {
+		off = amount * discount.Value / 100
+	}
+	if off > amount {
+		off = amount
+	}
+	return off
+}
+
+// ComputeTotals works out an order's totals from its priced lines: line
+// discounts first, then the order discount spread over the lines in proportion
+// to their value, then tax per line at its category rate, then the service
+// charge on net sales of dine-in orders and the delivery fee of delivery
+// orders. Line TotalPrice must already be price x quantity.
+func ComputeTotals(order *models.Order, settings *models.Settings) error {
+	lines := orderLines(order)
+	if err := validateDiscount(order.Discount); err != nil {
+		return err
+	}
+
+	subtotal := 0.0
+	afterLineDiscounts := 0.0
+	nets := make([]float64, len(lines))
+	for i, line := range lines {
+		if err := validateDiscount(line.discount); err != nil {
+			return err
+		}
+		off := discountOn(line.discount, line.gross)
+		*line.discountAmount = roundMoney(off)
+		nets[i] = line.gross - off
+		subtotal += line.gross
+		afterLineDiscounts += nets[i]
+	}
+	orderOff := discountOn(order.Discount, afterLineDiscounts)
+
+	byRate := make(map[float64]*models.TaxBreakdown)
+	netSales := 0.0
+	for i, line := range lines {
+		base := nets[i]
+		if afterLineDiscounts > 0 {
+			base -= orderOff * nets[i] / afterLineDiscounts
+		}
+		rate := settings.TaxRateFor(line.category)
+		*line.taxRate = rate
+
+		tax, net := base*rate/100, base
+		if settings.TaxInclusive {
+			net = base / (1 + rate/100)
+			tax = base - net
+		}
+		netSales += net
+		if rate == 0 {
+			continue
+		}
+		if byRate[rate] == nil {
+			byRate[rate] = &models.TaxBreakdown{Name: settings.TaxName, Rate: rate}
+		}
+		byRate[rate].Taxable += net
+		byRate[rate].Amount += tax
+	}
+
+	order.Taxes = []models.TaxBreakdown{}
+	order.TaxTotal = 0
+	for _, breakdown := range byRate {
+		breakdown.Taxable = roundMoney(breakdown.Taxable)
+		breakdown.Amount = roundMoney(breakdown.Amount)
+		order.Taxes = append(order.Taxes, *breakdown)
+		order.TaxTotal += breakdown.Amount
+	}
+	sort.Slice(order.Taxes, func(i, j int) bool {
+		return order.Taxes[i].Rate < order.Taxes[j].Rate
+	})
+
+	order.Subtotal = roundMoney(subtotal)
+	order.DiscountTotal = roundMoney(subtotal - afterLineDiscounts + orderOff)
+	order.TaxTotal = roundMoney(order.TaxTotal)
+	order.NetSales = roundMoney(netSales)
+	if settings.TaxInclusive {
+		// menu prices already hold the tax, keep the guest's total on the menu price
+		order.NetSales = roundMoney(afterLineDiscounts - orderOff - order.TaxTotal)
+	}
+	order.TaxInclusive = settings.TaxInclusive
+	order.ServiceCharge = 0
+	if order.OrderType() == models.OrderDineIn {
+		order.ServiceCharge = roundMoney(order.NetSales * settings.ServiceChargeRate / 100)
+	}
+	order.DeliveryFee = 0
+	if order.Delivery != nil {
+		order.DeliveryFee = roundMoney(order.Delivery.Fee)
+	}
+	order.TotalPrice = roundMoney(order.NetSales + order.TaxTotal + order.ServiceCharge + order.DeliveryFee)
+	return nil
+}
+
+func (usecase *UsecaseImplemented) GetSettings() (*models.Settings, error) {
+	return usecase.Repo.GetSettings()
+}
+
+func (usecase *UsecaseImplemented) UpdateSettings(settings *models.Settings) error {
+	rates := []float64{settings.TaxRate, settings.ServiceChargeRate}
+	for _, rate := range settings.CategoryTaxRates {
+		rates = append(rates, rate)
+	}
+	for _, rate := range rates {
+		if rate < 0 || rate > 100 {
+			return fmt.Errorf("%w: rates must be between 0 and 100", ErrInvalidSettings)
+		}
+	}
+	loyalty := settings.Loyalty
+	if loyalty.EarnRate < 0 || loyalty.PointValue < 0 || loyalty.MinRedeemPoints < 0 {
+		return fmt.Errorf("%w: loyalty rules cannot be negative", ErrInvalidSettings)
+	}
+	if err := validateTipPool(&settings.TipPool); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSettings, settings.Timezone)
+	}
+	if settings.TaxName == "" {
+		settings.TaxName = "VAT"
+	}
+	if settings.CategoryTaxRates == nil {
+		settings.CategoryTaxRates = map[string]float64{}
+	}
+	settings.UpdatedAt = time.Now().UTC()
+	return usecase.Repo.UpdateSettings(settings)
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// VoidOrder takes an order off the books with a reason. Before it was served
+// the order is simply cancelled. After that only a manager may void it; when
+// anyone else asks, the void waits for a manager's approval and the order is
+// left as it is. Payments already taken are refunded separately.
+func (usecase *UsecaseImplemented) VoidOrder(id string, request models.VoidRequest, employeeId primitive.ObjectID, isManager bool) (*models.Order, error) {
+	if !models.ValidVoidReason(request.Reason) {
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidVoid, request.Reason)
+	}
+	if request.Reason == models.VoidOther && request.Note == "" {
+		return nil, fmt.Errorf("%w: a note is required for reason %q", ErrInvalidVoid, models.VoidOther)
+	}
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status == models.OrderCancelled || order.Status == models.OrderVoided || order.Status == models.OrderMerged {
+		return nil, ErrOrderClosed
+	}
+
+	before := *order
+	now := time.Now().UTC()
+	order.Void = &models.Void{
+		Reason:      request.Reason,
+		Note:        request.Note,
+		RequestedBy: employeeId,
+		RequestedAt: now,
+	}
+	if order.ReachedServed() && !isManager {
+		if err := usecase.Repo.UpdateOrder(order); err != nil {
+			return nil, orderWriteError(err)
+		}
+		usecase.recordChange(&before, order, employeeId)
+		usecase.publishOrderEvent(models.EventOrderUpdated, *order)
+		return order, nil
+	}
+	return usecase.applyVoid(&before, order, employeeId, now)
+}
+
+// ApproveVoid lets a manager carry out a void that is waiting for approval.
+func (usecase *UsecaseImplemented) ApproveVoid(id string, managerId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if !order.VoidPending() || order.Status == models.OrderVoided {
+		return nil, fmt.Errorf("%w: no void is waiting for approval", ErrInvalidVoid)
+	}
+	before := *order
+	void := *order.Void
+	order.Void = &void
+	return usecase.applyVoid(&before, order, managerId, time.Now().UTC())
+}
+
+// RejectVoid drops a void that is waiting for approval.
+func (usecase *UsecaseImplemented) RejectVoid(id string, managerId primitive.ObjectID) (*models.Order, error) {
+	order, err := usecase.Repo.GetOrderById(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if !order.VoidPending() {
+		return nil, fmt.Errorf("%w: no void is waiting for approval", ErrInvalidVoid)
+	}
+	before := *order
+	order.Void = nil
+	if err := usecase.Repo.UpdateOrder(order); err != nil {
+		return nil, orderWriteError(err)
+	}
+	usecase.recordChange(&before, order, managerId)
+	usecase.publishOrderEvent(models.EventOrderUpdated, *order)
+	return order, nil
+}
+
+func (usecase *UsecaseImplemented) GetPendingVoids() ([]models.Order, error) {
+	return usecase.Repo.GetPendingVoids()
+}
+
+// applyVoid closes the order as cancelled, or as voided once it was served,
+// and frees its table.
+func (usecase *UsecaseImplemented) applyVoid(before, order *models.Order, employeeId primitive.ObjectID, at time.Time) (*models.Order, error) {
+	status := models.OrderCancelled
+	if order.ReachedServed() {
+		status = models.OrderVoided
+	}
+	order.Void.Approved = true
+	order.Void.ApprovedBy = employeeId
+	order.Void.ApprovedAt = at
+	order.StatusHistory = append(order.StatusHistory, models.StatusChange{
+		From:       order.Status,
+		To:         status,
+		EmployeeId: employeeId,
+		At:         at,
+	})
+	order.Status = status
+	if err := usecase.Repo.UpdateOrder(order); err != nil {
+		return nil, orderWriteError(err)
+	}
+	usecase.recordChange(before, order, employeeId)
+	usecase.releaseTable(order.TableNumber)
+	usecase.publishOrderEvent(models.EventStatusChanged, *order)
+	return order, nil
+}
@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+	"github.com/yesetoda/kushena/repositories"
+)
+
+// voucherAlphabet leaves out 0, O, 1 and I so codes read back without mistakes.
+const voucherAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const voucherCodeLength = 12
+
+func newVoucherCode() (string, error) {
+	code := make([]byte, voucherCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(voucherAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = voucherAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeVoucherCode accepts codes typed in lower case or with the dashes
+// and spaces printed to group them.
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// IssueVoucher creates a gift voucher worth its face value with a fresh code.
+func (usecase *UsecaseImplemented) IssueVoucher(voucher *models.Voucher, employeeId primitive.ObjectID) error {
+	if voucher.FaceValue <= 0 {
+		return fmt.Errorf("%w: face value must be positive", ErrInvalidVoucher)
+	}
+	now := time.Now().UTC()
+	if voucher.ExpiresAt.IsZero() {
+		voucher.ExpiresAt = now.AddDate(0, models.DefaultVoucherMonths, 0)
+	}
+	if !voucher.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expiry must be in the future", ErrInvalidVoucher)
+	}
+	voucher.FaceValue = roundMoney(voucher.FaceValue)
+	voucher.Balance = voucher.FaceValue
+	voucher.IssuedBy = employeeId
+	voucher.IssuedAt = now
+
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if voucher.Code, err = newVoucherCode(); err != nil {
+			return err
+		}
+		err = usecase.Repo.CreateVoucher(voucher)
+		if !errors.Is(err, repositories.ErrVoucherCodeTaken) {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	usecase.writeVoucherEntry(models.VoucherEntry{
+		VoucherId:  voucher.Id,
+		Kind:       models.VoucherIssued,
+		Amount:     voucher.FaceValue,
+		Balance:    voucher.Balance,
+		EmployeeId: employeeId,
+		At:         now,
+	})
+	return nil
+}
+
+func (usecase *UsecaseImplemented) GetVoucher(code string) (*models.Voucher, error) {
+	voucher, err := usecase.Repo.GetVoucherByCode(normalizeVoucherCode(code))
+	if err != nil {
+		return nil, ErrVoucherNotFound
+	}
+	return voucher, nil
+}
+
+// GetVoucherLedger returns the issue, redemptions and refunds of a voucher,
+// oldest first.
+func (usecase *UsecaseImplemented) GetVoucherLedger(code string) ([]models.VoucherEntry, error) {
+	voucher, err := usecase.GetVoucher(code)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.Repo.GetVoucherEntries(voucher.Id)
+}
+
+// VoucherQRCode returns the PNG code printed on a voucher.
+func (usecase *UsecaseImplemented) VoucherQRCode(code string) ([]byte, error) {
+	voucher, err := usecase.GetVoucher(code)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.QR.GenerateVoucherQRCode(voucher.Code)
+}
+
+// spendVoucher takes up to amount off the voucher named on a voucher payment.
+// A voucher holding less than amount pays what it has left and the rest stays
+// due. It returns the voucher and how much was taken.
+func (usecase *UsecaseImplemented) spendVoucher(request models.PaymentRequest, amount float64, at time.Time) (*models.Voucher, float64, error) {
+	if request.VoucherCode == "" {
+		return nil, 0, fmt.Errorf("%w: a voucher code is required", ErrInvalidPayment)
+	}
+	voucher, err := usecase.GetVoucher(request.VoucherCode)
+	if err != nil {
+		return nil, 0, err
+	}
+	if voucher.Expired(at) {
+		return nil, 0, fmt.Errorf("%w: voucher expired on %s", ErrInvalidVoucher, voucher.ExpiresAt.Local().Format("2006-01-02"))
+	}
+	if voucher.Balance <= 0 {
+		return nil, 0, fmt.Errorf("%w: voucher has no balance left", ErrInvalidVoucher)
+	}
+	amount = roundMoney(math.Min(amount, voucher.Balance))
+	balance, err := usecase.Repo.SpendVoucher(voucher.Id, amount, at)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidVoucher, err)
+	}
+	voucher.Balance = roundMoney(balance)
+	return voucher, amount, nil
+}
+
+// creditVoucher puts money back on the voucher a payment was taken from.
+func (usecase *UsecaseImplemented) creditVoucher(payment *models.Payment, amount float64, kind string, employeeId primitive.ObjectID, at time.Time) {
+	balance, err := usecase.Repo.CreditVoucher(payment.VoucherId, amount)
+	if err != nil {
+		log.Printf("failed to credit %.2f back to voucher %s: %v", amount, payment.VoucherId.Hex(), err)
+		return
+	}
+	if kind == "" {
+		return
+	}
+	usecase.writeVoucherEntry(models.VoucherEntry{
+		VoucherId:  payment.VoucherId,
+		Kind:       kind,
+		Amount:     amount,
+		Balance:    roundMoney(balance),
+		OrderId:    payment.OrderId,
+		PaymentId:  payment.Id,
+		EmployeeId: employeeId,
+		At:         at,
+	})
+}
+
+func (usecase *UsecaseImplemented) writeVoucherEntry(entry models.VoucherEntry) {
+	if err := usecase.Repo.CreateVoucherEntry(&entry); err != nil {
+		log.Printf("failed to write ledger entry for voucher %s: %v", entry.VoucherId.Hex(), err)
+	}
+}
@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/models"
+)
+
+// turnTime is how long a seated party is expected to hold its table, learned
+// from the dine-in orders paid over the last four weeks.
+func (usecase *UsecaseImplemented) turnTime(now time.Time) time.Duration {
+	fallback := models.DefaultTurnMinutes * time.Minute
+	average, count, err := usecase.Repo.AverageTimeToPay(now.AddDate(0, 0, -28))
+	if err != nil {
+		log.Printf("failed to learn table turn time: %v", err)
+		return fallback
+	}
+	if count == 0 || average <= 0 {
+		return fallback
+	}
+	return average
+}
+
+// estimateWaits works out, in queue order, how long each waiting party has
+// left to wait. Every table is given the time it is expected to free up: now
+// if it is free, or its oldest open order's start plus the turn time if it is
+// occupied. Each party takes the fitting table that frees up first, which
+// then stays busy for another turn. Reserved tables are left out.
+func (usecase *UsecaseImplemented) estimateWaits(parties []models.WaitlistEntry, now time.Time) error {
+	tables, err := usecase.Repo.GetAllTables()
+	if err != nil {
+		return err
+	}
+	turn := usecase.turnTime(now)
+
+	freeAt := make(map[int]time.Time)
+	capacity := make(map[int]int)
+	for _, table := range tables {
+		if table.Status == models.TableReserved {
+			continue
+		}
+		capacity[table.Number] = table.Capacity
+		freeAt[table.Number] = now
+		if table.Status != models.TableOccupied {
+			continue
+		}
+		open, err := usecase.Repo.GetOpenOrdersByTable(table.Number)
+		if err != nil {
+			return err
+		}
+		if len(open) > 0 && open[0].CreatedAt.Add(turn).After(now) {
+			freeAt[table.Number] = open[0].CreatedAt.Add(turn)
+		}
+	}
+
+	for i := range parties {
+		best := 0
+		for number, seats := range capacity {
+			if seats < parties[i].PartySize {
+				continue
+			}
+			if best == 0 || freeAt[number].Before(freeAt[best]) ||
+				(freeAt[number].Equal(freeAt[best]) && seats < capacity[best]) ||
+				(freeAt[number].Equal(freeAt[best]) && seats == capacity[best] && number < best) {
+				best = number
+			}
+		}
+		if best == 0 {
+			// no table seats the party any more
+			parties[i].EstimatedMinutes = -1
+			continue
+		}
+		wait := freeAt[best].Sub(now)
+		parties[i].EstimatedMinutes = int((wait + time.Minute - 1) / time.Minute)
+		freeAt[best] = freeAt[best].Add(turn)
+	}
+	return nil
+}
+
+// JoinWaitlist puts a walk-in party at the back of the queue and quotes it a
+// wait.
+func (usecase *UsecaseImplemented) JoinWaitlist(entry *models.WaitlistEntry, employeeId primitive.ObjectID) error {
+	if entry.Name == "" {
+		return fmt.Errorf("%w: a name is required", ErrInvalidWaitlistEntry)
+	}
+	if entry.PartySize <= 0 {
+		return fmt.Errorf("%w: party size must be positive", ErrInvalidWaitlistEntry)
+	}
+	tables, err := usecase.Repo.GetAllTables()
+	if err != nil {
+		return err
+	}
+	fits := false
+	for _, table := range tables {
+		fits = fits || table.Capacity >= entry.PartySize
+	}
+	if !fits {
+		return fmt.Errorf("%w: no table seats %d", ErrInvalidWaitlistEntry, entry.PartySize)
+	}
+
+	now := time.Now().UTC()
+	entry.Status = models.WaitlistWaiting
+	entry.JoinedAt = now
+	entry.AddedBy = employeeId
+	queue, err := usecase.Repo.GetWaitingParties()
+	if err != nil {
+		return err
+	}
+	queue = append(queue, *entry)
+	if err := usecase.estimateWaits(queue, now); err != nil {
+		return err
+	}
+	entry.EstimatedMinutes = queue[len(queue)-1].EstimatedMinutes
+	entry.QuotedMinutes = entry.EstimatedMinutes
+	return usecase.Repo.CreateWaitlistEntry(entry)
+}
+
+// GetWaitlist returns the waiting parties in queue order with fresh estimates.
+func (usecase *UsecaseImplemented) GetWaitlist() ([]models.WaitlistEntry, error) {
+	queue, err := usecase.Repo.GetWaitingParties()
+	if err != nil {
+		return nil, err
+	}
+	if err := usecase.estimateWaits(queue, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// SeatWaitlistParty seats a waiting party at a table and opens its order.
+func (usecase *UsecaseImplemented) SeatWaitlistParty(id string, tableNumber int, employeeId primitive.ObjectID) (*models.WaitlistEntry, *models.Order, error) {
+	entry, err := usecase.Repo.GetWaitlistEntryById(id)
+	if err != nil {
+		return nil, nil, ErrWaitlistEntryNotFound
+	}
+	if entry.Status != models.WaitlistWaiting {
+		return nil, nil, fmt.Errorf("%w: party is already %s", ErrInvalidTransition, entry.Status)
+	}
+	table, err := usecase.Repo.GetTableByNumber(tableNumber)
+	if err != nil {
+		return nil, nil, ErrTableNotFound
+	}
+	if table.Capacity < entry.PartySize {
+		return nil, nil, fmt.Errorf("%w: table %d seats %d, party is %d", ErrInvalidWaitlistEntry, tableNumber, table.Capacity, entry.PartySize)
+	}
+	open, err := usecase.Repo.GetOpenOrdersByTable(tableNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(open) > 0 {
+		return nil, nil, fmt.Errorf("%w: table %d has open orders", ErrTableInUse, tableNumber)
+	}
+
+	// take the party off the queue first so two hosts cannot both seat it
+	now := time.Now().UTC()
+	seated := bson.M{
+		"status":       models.WaitlistSeated,
+		"seated_at":    now,
+		"table_number": tableNumber,
+		"closed_by":    employeeId,
+	}
+	if err := usecase.Repo.MoveWaitlistEntry(entry.Id, models.WaitlistWaiting, seated, nil); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+	order, err := usecase.createOrder(models.Order{
+		Type:        models.OrderDineIn,
+		TableNumber: tableNumber,
+		EmployeeId:  employeeId,
+		CreatedAt:   now,
+		Source:      models.SourceStaff,
+	}, models.OrderPending, employeeId)
+	if err != nil {
+		back := bson.M{"seated_at": "", "table_number": "", "closed_by": ""}
+		if err := usecase.Repo.MoveWaitlistEntry(entry.Id, models.WaitlistSeated, bson.M{"status": models.WaitlistWaiting}, back); err != nil {
+			log.Printf("failed to put party %s back on the waitlist: %v", entry.Id.Hex(), err)
+		}
+		return nil, nil, err
+	}
+	if err := usecase.Repo.MoveWaitlistEntry(entry.Id, models.WaitlistSeated, bson.M{"order_id": order.Id}, nil); err != nil {
+		log.Printf("failed to link order %s to waitlist entry %s: %v", order.Id.Hex(), entry.Id.Hex(), err)
+	}
+
+	entry.Status = models.WaitlistSeated
+	entry.SeatedAt = now
+	entry.TableNumber = tableNumber
+	entry.OrderId = order.Id
+	entry.ClosedBy = employeeId
+	return entry, order, nil
+}
+
+// WalkAwayWaitlistParty records that a waiting party left before being seated.
+func (usecase *UsecaseImplemented) WalkAwayWaitlistParty(id string, employeeId primitive.ObjectID) (*models.WaitlistEntry, error) {
+	entry, err := usecase.Repo.GetWaitlistEntryById(id)
+	if err != nil {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if entry.Status != models.WaitlistWaiting {
+		return nil, fmt.Errorf("%w: party is already %s", ErrInvalidTransition, entry.Status)
+	}
+	now := time.Now().UTC()
+	left := bson.M{
+		"status":    models.WaitlistWalkedAway,
+		"left_at":   now,
+		"closed_by": employeeId,
+	}
+	if err := usecase.Repo.MoveWaitlistEntry(entry.Id, models.WaitlistWaiting, left, nil); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+	entry.Status = models.WaitlistWalkedAway
+	entry.LeftAt = now
+	entry.ClosedBy = employeeId
+	return entry, nil
+}
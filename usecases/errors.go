@@ -0,0 +1,42 @@
+package usecases
+
+import "errors"
+
+var (
+	ErrItemNotFound          = errors.New("item not found")
+	ErrInvalidOrder          = errors.New("invalid order")
+	ErrOrderNotFound         = errors.New("order not found")
+	ErrInvalidTransition     = errors.New("invalid status transition")
+	ErrOrderClosed           = errors.New("order is closed")
+	ErrTableNotFound         = errors.New("table not found")
+	ErrInvalidTable          = errors.New("invalid table")
+	ErrTableInUse            = errors.New("table in use")
+	ErrInvalidSplit          = errors.New("invalid bill split")
+	ErrPaymentNotFound       = errors.New("payment not found")
+	ErrInvalidPayment        = errors.New("invalid payment")
+	ErrInvalidDiscount       = errors.New("invalid discount")
+	ErrInvalidSettings       = errors.New("invalid settings")
+	ErrInvalidMenuItem       = errors.New("invalid menu item")
+	ErrVersionConflict       = errors.New("order was changed by someone else, reload it and retry")
+	ErrInvalidVoid           = errors.New("invalid void")
+	ErrInvalidQuery          = errors.New("invalid query")
+	ErrIdempotencyMismatch   = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still being processed")
+	ErrInvalidCourse         = errors.New("invalid course")
+	ErrGuestTokenExpired     = errors.New("guest token is no longer valid for this table")
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrInvalidReservation    = errors.New("invalid reservation")
+	ErrTableBooked           = errors.New("table is already booked for that time")
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrInvalidWaitlistEntry  = errors.New("invalid waitlist entry")
+	ErrCustomerNotFound      = errors.New("customer not found")
+	ErrInvalidCustomer       = errors.New("invalid customer")
+	ErrCustomerExists        = errors.New("a customer with this phone number already exists")
+	ErrVoucherNotFound       = errors.New("voucher not found")
+	ErrInvalidVoucher        = errors.New("invalid voucher")
+	ErrPricingRuleNotFound   = errors.New("pricing rule not found")
+	ErrInvalidPricingRule    = errors.New("invalid pricing rule")
+	ErrItemUnavailable       = errors.New("item is not available")
+	ErrMenuScheduleNotFound  = errors.New("menu schedule not found")
+	ErrInvalidMenuSchedule   = errors.New("invalid menu schedule")
+)
@@ -1,8 +1,12 @@
 package usecases
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/yesetoda/kushena/infrastructures/receipt_services"
 	"github.com/yesetoda/kushena/models"
 
 )
@@ -26,22 +30,116 @@ type UsecaseInterface interface {
 	MonthlyReport() ([]byte, error)
 	YearlyReport() ([]byte, error)
 
-	CreateOrder(order models.Order) error
-	UpdateOrder(order *models.Order) error
-	DeleteOrder(id string) error
+	CreateOrder(order models.Order) (*models.Order, error)
+	UpdateOrder(patch models.OrderPatch, employeeId primitive.ObjectID) (*models.Order, error)
+	TransitionOrder(id, status string, employeeId primitive.ObjectID) (*models.Order, error)
+	VoidOrder(id string, request models.VoidRequest, employeeId primitive.ObjectID, isManager bool) (*models.Order, error)
+	ApproveVoid(id string, managerId primitive.ObjectID) (*models.Order, error)
+	RejectVoid(id string, managerId primitive.ObjectID) (*models.Order, error)
+	GetPendingVoids() ([]models.Order, error)
 	GetOrderById(id string) (*models.Order, error)
-	GetAllOrders() ([]models.Order, error)
-	GetAllMyOrders(id string) ([]models.Order, error)
+	FindOrders(query models.OrderQuery) (*models.OrderPage, error)
+	GetOrderHistory(id string) ([]models.OrderChange, error)
+	AssignDriver(id string, driverId, employeeId primitive.ObjectID) (*models.Order, error)
+	FireCourse(id string, course int, employeeId primitive.ObjectID) (*models.Order, error)
+	ServeCourse(id string, course int, employeeId primitive.ObjectID) (*models.Order, error)
+	GetOrderCourses(id string) ([]models.CourseView, error)
+
+	SubscribeOrderEvents(seq int64) ([]models.OrderEvent, chan models.OrderEvent, error)
+	UnsubscribeOrderEvents(ch chan models.OrderEvent)
+	GetOrderEventsAfter(seq int64) ([]models.OrderEvent, error)
+
+	CreateTable(table *models.Table) error
+	UpdateTable(table *models.Table) error
+	DeleteTable(id string) error
+	GetTableById(id string) (*models.Table, error)
+	GetAllTables() ([]models.Table, error)
+	TransferOrder(id string, tableNumber int, employeeId primitive.ObjectID) (*models.Order, error)
+	MergeTables(from, to int, employeeId primitive.ObjectID) (*models.Order, error)
+
+	SplitOrder(id string, request models.SplitRequest, employeeId primitive.ObjectID) (*models.Order, error)
+	SettleBillPart(id string, number int, employeeId primitive.ObjectID) (*models.Order, error)
+
+	RecordPayment(orderId string, request models.PaymentRequest, employeeId primitive.ObjectID) (*models.Payment, error)
+	GetOrderPayments(orderId string) ([]models.Payment, error)
+	RefundPayment(id string, request models.RefundRequest, employeeId primitive.ObjectID) (*models.Payment, error)
+	ReconcileCash(employeeId primitive.ObjectID, request models.ReconciliationRequest) (*models.CashReconciliation, error)
+	GetReconciliations(day time.Time) ([]models.CashReconciliation, error)
+
+	GetReceipt(orderId string, employeeId primitive.ObjectID) (*receipt_services.Receipt, error)
+
+	GetSettings() (*models.Settings, error)
+	UpdateSettings(settings *models.Settings) error
 
 	CreateFood(food models.Food) error
 	UpdateFood(food *models.Food) error
 	DeleteFood(id string) error
 	GetFoodById(id string) (*models.Food, error)
-	GetAllFoods() ([]models.Food, error)
+	GetAllFoods(all bool) ([]models.Food, error)
 
 	CreateDrink(drink *models.Drink) error
 	UpdateDrink(drink *models.Drink) error
 	DeleteDrink(id string) error
 	GetDrinkById(id string) (*models.Drink, error)
-	GetAllDrinks() ([]models.Drink, error)
+	GetAllDrinks(all bool) ([]models.Drink, error)
+
+	BeginIdempotentRequest(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	FinishIdempotentRequest(id string, statusCode int, contentType string, body []byte)
+
+	GetPublicMenu() (*models.Menu, error)
+	TableQRCode(id string) ([]byte, error)
+
+	IssueGuestToken(tableId string) (*models.GuestToken, error)
+	CheckGuestToken(claims *models.GuestClaims) error
+	CreateGuestOrder(claims *models.GuestClaims, order models.Order) (*models.Order, error)
+	GetGuestOrders(claims *models.GuestClaims) ([]models.Order, error)
+
+	CreateReservation(reservation *models.Reservation, employeeId primitive.ObjectID) error
+	UpdateReservation(reservation *models.Reservation) error
+	GetReservationById(id string) (*models.Reservation, error)
+	GetDailyReservations(day time.Time) ([]models.Reservation, error)
+	ConfirmReservation(id string, tableNumber int, employeeId primitive.ObjectID) (*models.Reservation, error)
+	SeatReservation(id string, tableNumber int, employeeId primitive.ObjectID) (*models.Reservation, error)
+	NoShowReservation(id string, employeeId primitive.ObjectID) (*models.Reservation, error)
+	CancelReservation(id string, employeeId primitive.ObjectID) (*models.Reservation, error)
+
+	JoinWaitlist(entry *models.WaitlistEntry, employeeId primitive.ObjectID) error
+	GetWaitlist() ([]models.WaitlistEntry, error)
+	SeatWaitlistParty(id string, tableNumber int, employeeId primitive.ObjectID) (*models.WaitlistEntry, *models.Order, error)
+	WalkAwayWaitlistParty(id string, employeeId primitive.ObjectID) (*models.WaitlistEntry, error)
+
+	CreateCustomer(customer *models.Customer) error
+	UpdateCustomer(customer *models.Customer) (*models.Customer, error)
+	GetCustomerById(id string) (*models.Customer, error)
+	GetCustomerByPhone(phone string) (*models.Customer, error)
+	GetCustomerPoints(id string) ([]models.LoyaltyEntry, error)
+	AttachCustomer(orderId, phone string, employeeId primitive.ObjectID) (*models.Order, error)
+
+	IssueVoucher(voucher *models.Voucher, employeeId primitive.ObjectID) error
+	GetVoucher(code string) (*models.Voucher, error)
+	GetVoucherLedger(code string) ([]models.VoucherEntry, error)
+	VoucherQRCode(code string) ([]byte, error)
+
+	RecordTip(orderId string, request models.TipRequest, employeeId primitive.ObjectID) (*models.Tip, error)
+	GetOrderTips(orderId string) ([]models.Tip, error)
+	TipPayout(first, last time.Time) (*models.TipPayout, error)
+
+	CreatePricingRule(rule *models.PricingRule, employeeId primitive.ObjectID) error
+	UpdatePricingRule(rule *models.PricingRule) (*models.PricingRule, error)
+	DeletePricingRule(id string) error
+	GetPricingRuleById(id string) (*models.PricingRule, error)
+	GetPricingRules() ([]models.PricingRule, error)
+
+	CreateCombo(combo *models.Combo) error
+	UpdateCombo(combo *models.Combo) error
+	DeleteCombo(id string) error
+	GetComboById(id string) (*models.Combo, error)
+	GetAllCombos() ([]models.Combo, error)
+
+	SetFoodSoldOut(id string, soldOut bool) (*models.Food, error)
+	SetDrinkSoldOut(id string, soldOut bool) (*models.Drink, error)
+	CreateMenuSchedule(schedule *models.MenuSchedule) error
+	UpdateMenuSchedule(schedule *models.MenuSchedule) error
+	DeleteMenuSchedule(id string) error
+	GetMenuSchedules() ([]models.MenuSchedule, error)
 }
@@ -1,65 +1,228 @@
 package usecases
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/yesetoda/kushena/infrastructures/event_services"
+	"github.com/yesetoda/kushena/infrastructures/qr_services"
 	"github.com/yesetoda/kushena/models"
 	"github.com/yesetoda/kushena/repositories"
 )
 
 type UsecaseImplemented struct {
-	Repo repositories.RepositoryInterface
+	Repo   repositories.RepositoryInterface
+	Events *event_services.Broker
+	QR     qr_services.QRService
 }
 
 func NewUsecase(repo repositories.RepositoryInterface) UsecaseInterface {
 	return &UsecaseImplemented{
-		Repo: repo,
+		Repo:   repo,
+		Events: event_services.NewBroker(),
+		QR:     qr_services.NewQRServiceFromEnv(),
 	}
 }
 
-func (usecase *UsecaseImplemented) CreateOrder(order models.Order) error {
-	err := usecase.Repo.CreateOrder(order)
-	return err
+func (usecase *UsecaseImplemented) CreateOrder(order models.Order) (*models.Order, error) {
+	order.Source = models.SourceStaff
+	order.GuestSession = ""
+	return usecase.createOrder(order, models.OrderPending, order.EmployeeId)
 }
 
-func (usecase *UsecaseImplemented) UpdateOrder(order *models.Order) error {
-	err := usecase.Repo.UpdateOrder(order)
-	return err
+// createOrder validates, prices and stores a new order in its first status,
+// recording who placed it; a guest order is placed by no employee.
+func (usecase *UsecaseImplemented) createOrder(order models.Order, status string, placedBy primitive.ObjectID) (*models.Order, error) {
+	if err := usecase.validateOrderType(&order); err != nil {
+		return nil, err
+	}
+	if err := usecase.checkCustomer(order.CustomerId); err != nil {
+		return nil, err
+	}
+	if err := usecase.PriceOrder(&order, nil, order.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := usecase.checkOffered(&order, nil, order.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := prepareCourses(&order, order.CreatedAt); err != nil {
+		return nil, err
+	}
+	order.Version = 1
+	order.Status = status
+	// splits, voids and merges only come about through their own endpoints
+	order.SplitMode = ""
+	order.Splits = nil
+	order.Void = nil
+	order.MergedInto = primitive.NilObjectID
+	order.StatusHistory = []models.StatusChange{{
+		To:         status,
+		EmployeeId: placedBy,
+		At:         order.CreatedAt,
+	}}
+	if err := usecase.Repo.CreateOrder(&order); err != nil {
+		return nil, err
+	}
+	if order.Type == models.OrderDineIn {
+		usecase.occupyTable(order.TableNumber)
+	}
+	usecase.publishOrderEvent(models.EventOrderCreated, order)
+	return &order, nil
+}
+
+// UpdateOrder applies a partial update to the lines and discount of an open
+// order. Fields missing from the patch are left as they are; status and table
+// only change through TransitionOrder and TransferOrder. The patch must carry
+// the version the client last read, otherwise ErrVersionConflict is returned.
+func (usecase *UsecaseImplemented) UpdateOrder(patch models.OrderPatch, employeeId primitive.ObjectID) (*models.Order, error) {
+	existing, err := usecase.Repo.GetOrderById(patch.Id.Hex())
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if existing.IsClosed() {
+		return nil, ErrOrderClosed
+	}
+	if existing.HasSettledPart() {
+		return nil, fmt.Errorf("%w: part of the check is already settled", ErrOrderClosed)
+	}
+	if len(existing.Splits) > 0 {
+		// an edit drops the split, and with it what was paid towards each part
+		payments, err := usecase.Repo.GetPaymentsByOrder(existing.Id)
+		if err != nil {
+			return nil, err
+		}
+		if len(payments) > 0 {
+			return nil, fmt.Errorf("%w: part of the split check is already paid", ErrOrderClosed)
+		}
+	}
+	if patch.Version != existing.Version {
+		return nil, ErrVersionConflict
+	}
+
+	order := *existing
+	order.Foods = append([]models.FoodOrder(nil), existing.Foods...)
+	order.Drinks = append([]models.DrinkOrder(nil), existing.Drinks...)
+	order.Combos = append([]models.ComboOrder(nil), existing.Combos...)
+	if patch.Foods != nil {
+		order.Foods = *patch.Foods
+	}
+	if patch.Drinks != nil {
+		order.Drinks = *patch.Drinks
+	}
+	if patch.Combos != nil {
+		order.Combos = *patch.Combos
+	}
+	if patch.Discount != nil {
+		order.Discount = patch.Discount
+	}
+	if patch.RemoveDiscount {
+		order.Discount = nil
+	}
+	if err := usecase.PriceOrder(&order, existing, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := usecase.checkOffered(&order, existing, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := prepareCourses(&order, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	// changed lines invalidate any split, the check has to be split again
+	order.SplitMode = ""
+	order.Splits = nil
 
+	set := pricedFields(&order)
+	unset := bson.M{"split_mode": "", "splits": ""}
+	if order.Discount != nil {
+		set["discount"] = order.Discount
+	} else {
+		unset["discount"] = ""
+	}
+	if err := usecase.Repo.PatchOrder(order.Id, existing.Version, set, unset); err != nil {
+		return nil, orderWriteError(err)
+	}
+	order.Version++
+	usecase.recordChange(existing, &order, employeeId)
+	usecase.publishOrderEvent(models.EventOrderUpdated, order)
+	return &order, nil
 }
-func (usecase *UsecaseImplemented) DeleteOrder(id string) error {
-	err := usecase.Repo.DeleteOrder(id)
-	return err
 
+// pricedFields are the stored fields of an order that pricing sets: its lines
+// and totals.
+func pricedFields(order *models.Order) bson.M {
+	return bson.M{
+		"foods":          order.Foods,
+		"drinks":         order.Drinks,
+		"combos":         order.Combos,
+		"subtotal":       order.Subtotal,
+		"discount_total": order.DiscountTotal,
+		"net_sales":      order.NetSales,
+		"taxes":          order.Taxes,
+		"tax_total":      order.TaxTotal,
+		"tax_inclusive":  order.TaxInclusive,
+		"service_charge": order.ServiceCharge,
+		"delivery_fee":   order.DeliveryFee,
+		"total_price":    order.TotalPrice,
+	}
 }
+
 func (usecase *UsecaseImplemented) GetOrderById(id string) (*models.Order, error) {
 	var order *models.Order
 	order, err := usecase.Repo.GetOrderById(id)
 	return order, err
 
 }
-func (usecase *UsecaseImplemented) GetAllOrders() ([]models.Order, error) {
-	var orders []models.Order
-	orders, err := usecase.Repo.GetAllOrders()
-	if err != nil {
-		return nil, err
+// FindOrders returns one page of the orders matching query.
+func (usecase *UsecaseImplemented) FindOrders(query models.OrderQuery) (*models.OrderPage, error) {
+	switch query.Sort {
+	case "":
+		query.Sort = models.SortNewest
+	case models.SortNewest, models.SortOldest, models.SortTotalDesc, models.SortTotalAsc:
+	default:
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidQuery, query.Sort)
 	}
-	return orders, nil
-}
-
-func (usecase *UsecaseImplemented) GetAllMyOrders(id string) ([]models.Order, error) {
-	var orders []models.Order
-	orders, err := usecase.Repo.GetAllMyOrders(id)
-	if err != nil {
-		return nil, err
+	if query.Limit == 0 {
+		query.Limit = models.DefaultPerPage
+	}
+	if query.Limit < 0 || query.Limit > models.MaxPerPage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, models.MaxPerPage)
+	}
+	if query.Type != "" && !models.ValidOrderType(query.Type) {
+		return nil, fmt.Errorf("%w: unknown order type %q", ErrInvalidQuery, query.Type)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+	if query.MinTotal != nil && query.MaxTotal != nil && *query.MinTotal > *query.MaxTotal {
+		return nil, fmt.Errorf("%w: min_total is above max_total", ErrInvalidQuery)
+	}
+	page, err := usecase.Repo.FindOrders(query)
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
 	}
-	return orders, nil
+	return page, err
 }
 
 func (usecase *UsecaseImplemented) CreateFood(food models.Food) error {
+	if err := ValidateModifierGroups(food.ModifierGroups, nil); err != nil {
+		return err
+	}
 	err := usecase.Repo.CreateFood(food)
 	return err
 
 }
 func (usecase *UsecaseImplemented) UpdateFood(food *models.Food) error {
+	if err := ValidateModifierGroups(food.ModifierGroups, nil); err != nil {
+		return err
+	}
+	// sold out only changes through SetFoodSoldOut
+	if existing, err := usecase.Repo.GetFoodById(food.Id.Hex()); err == nil {
+		food.SoldOut = existing.SoldOut
+	}
 	err := usecase.Repo.UpdateFood(food)
 	return err
 
@@ -75,23 +238,46 @@ func (usecase *UsecaseImplemented) GetFoodById(id string) (*models.Food, error)
 	return food, err
 
 }
-func (usecase *UsecaseImplemented) GetAllFoods() ([]models.Food, error) {
+// GetAllFoods lists the foods on offer now, or every food if all is set.
+func (usecase *UsecaseImplemented) GetAllFoods(all bool) ([]models.Food, error) {
 	var foods []models.Food
 	foods, err := usecase.Repo.GetAllFoods()
 	if err != nil {
 		return nil, err
 	}
-
-	return foods, nil
+	if all {
+		return foods, nil
+	}
+	offer, err := usecase.menuOfferAt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	offered := []models.Food{}
+	for i := range foods {
+		if offer.food(&foods[i]) {
+			offered = append(offered, foods[i])
+		}
+	}
+	return offered, nil
 
 }
 
 func (usecase *UsecaseImplemented) CreateDrink(drink *models.Drink) error {
+	if err := ValidateModifierGroups(drink.ModifierGroups, drink.Variants); err != nil {
+		return err
+	}
 	err := usecase.Repo.CreateDrink(drink)
 	return err
 
 }
 func (usecase *UsecaseImplemented) UpdateDrink(drink *models.Drink) error {
+	if err := ValidateModifierGroups(drink.ModifierGroups, drink.Variants); err != nil {
+		return err
+	}
+	// sold out only changes through SetDrinkSoldOut
+	if existing, err := usecase.Repo.GetDrinkById(drink.Id.Hex()); err == nil {
+		drink.SoldOut = existing.SoldOut
+	}
 	err := usecase.Repo.UpdateDrink(drink)
 	return err
 
@@ -107,12 +293,26 @@ func (usecase *UsecaseImplemented) GetDrinkById(id string) (*models.Drink, error
 	return drink, err
 
 }
-func (usecase *UsecaseImplemented) GetAllDrinks() ([]models.Drink, error) {
+// GetAllDrinks lists the drinks on offer now, or every drink if all is set.
+func (usecase *UsecaseImplemented) GetAllDrinks(all bool) ([]models.Drink, error) {
 	var drinks []models.Drink
 	drinks, err := usecase.Repo.GetAllDrinks()
 	if err != nil {
 		return nil, err
 	}
-	return drinks, nil
+	if all {
+		return drinks, nil
+	}
+	offer, err := usecase.menuOfferAt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	offered := []models.Drink{}
+	for i := range drinks {
+		if offer.drink(&drinks[i]) {
+			offered = append(offered, drinks[i])
+		}
+	}
+	return offered, nil
 
 }